@@ -0,0 +1,100 @@
+package conf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrEntryNotFound is an error returned if an archive does not contain a given entry
+var ErrEntryNotFound = errors.New("entry not found")
+
+// NewArchiveReader creates an instance of the Parser to read a given entry of a zip or tar archive
+// The type of the archive is selected by the extension: `.zip`, `.tar`, `.tar.gz` or `.tgz`.
+// The entry is read into memory immediately and the parser is selected from the Formats
+// by the extension of the entry, if registered.
+func NewArchiveReader(archivePath, entry string) (Parser, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case strings.HasSuffix(archivePath, ".zip"):
+		data, err = readZipEntry(archivePath, entry)
+	case strings.HasSuffix(archivePath, ".tar.gz"), strings.HasSuffix(archivePath, ".tgz"):
+		data, err = readTarEntry(archivePath, entry, true)
+	case strings.HasSuffix(archivePath, ".tar"):
+		data, err = readTarEntry(archivePath, entry, false)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, archivePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewStreamParser(bytes.NewReader(data))
+	if parse, ok := Formats[filepath.Ext(entry)]; ok {
+		p.WithParser(parse)
+	}
+
+	return p, nil
+}
+
+func readZipEntry(archivePath, entry string) ([]byte, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	f, err := r.Open(entry)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s in %s", ErrEntryNotFound, entry, archivePath)
+		}
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	return io.ReadAll(f)
+}
+
+func readTarEntry(archivePath, entry string, gzipped bool) ([]byte, error) {
+	f, err := os.Open(archivePath) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	var stream io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close() //nolint:errcheck
+		stream = gz
+	}
+
+	tr := tar.NewReader(stream)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %s in %s", ErrEntryNotFound, entry, archivePath)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag == tar.TypeReg && filepath.Clean(header.Name) == filepath.Clean(entry) {
+			return io.ReadAll(tr)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package conf_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func writeZip(tb testing.TB, files map[string]string) string {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(tb, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(tb, err)
+	}
+	require.NoError(tb, w.Close())
+
+	path := filepath.Join(tb.TempDir(), "config.zip")
+	require.NoError(tb, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	return path
+}
+
+func writeTarGz(tb testing.TB, files map[string]string) string {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(tb, w.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := w.Write([]byte(content))
+		require.NoError(tb, err)
+	}
+	require.NoError(tb, w.Close())
+	require.NoError(tb, gz.Close())
+
+	path := filepath.Join(tb.TempDir(), "config.tar.gz")
+	require.NoError(tb, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	return path
+}
+
+func TestArchiveReader(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"config/app.json": `{"foo": 1, "bar": {"baz": "qux"}}`,
+		"config/data.txt": `foo:1;bar:2`,
+	}
+
+	for name, path := range map[string]string{
+		"zip":    writeZip(t, files),
+		"tar.gz": writeTarGz(t, files),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			jsonParser, err := conf.NewArchiveReader(path, "config/app.json")
+			require.NoError(t, err)
+			txtParser, err := conf.NewArchiveReader(path, "config/data.txt")
+			require.NoError(t, err)
+
+			c := conf.New().WithReaders(
+				jsonParser.WithPrefix("json"),
+				txtParser.WithParser(testParseFunc).WithPrefix("txt"),
+			)
+			require.NoError(t, c.Load(context.Background()))
+			require.Equal(t, 1, c.GetInt("json.foo"))
+			require.Equal(t, "qux", c.GetString("json.bar.baz"))
+			require.Equal(t, 2, c.GetInt("txt.bar"))
+
+			_, err = conf.NewArchiveReader(path, "config/fake.json")
+			require.ErrorIs(t, err, conf.ErrEntryNotFound)
+		})
+	}
+}
+
+func TestArchiveReader_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := conf.NewArchiveReader("testdata/config.rar", "config.json")
+	require.ErrorIs(t, err, conf.ErrUnknownFormat)
+
+	_, err = conf.NewArchiveReader("testdata/fake.zip", "config.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = conf.NewArchiveReader("testdata/fake.tgz", "config.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,52 @@
+package conf
+
+import (
+	"context"
+	"strings"
+)
+
+type chain struct {
+	readers []Reader
+}
+
+func (c *chain) Prefix() string {
+	return ""
+}
+
+func (c *chain) Read(ctx context.Context) (interface{}, error) {
+	var res interface{}
+	for _, reader := range c.readers {
+		data, err := reader.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res = merge(res, nest(reader.Prefix(), data))
+	}
+
+	return res, nil
+}
+
+// nest places a given data under a given dot separated prefix, e.g. `a.b` gives `{"a": {"b": data}}`
+func nest(prefix string, data interface{}) interface{} {
+	if prefix == "" {
+		return data
+	}
+
+	parts := strings.Split(prefix, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		data = map[string]interface{}{parts[i]: data}
+	}
+
+	return data
+}
+
+// Chain creates an instance of the Reader to compose the given readers into a single one
+// The data of the readers is deeply merged in the given order, so the values of the later readers take precedence,
+// and the prefixes of the readers are respected.
+// Returns the error of the first failing reader.
+func Chain(readers ...Reader) Reader {
+	return &chain{
+		readers: readers,
+	}
+}
@@ -0,0 +1,44 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	reader := conf.Chain(
+		newReader(t, "", map[string]interface{}{
+			"db": map[string]interface{}{"host": "localhost", "port": 5432},
+		}, nil),
+		newReader(t, "", map[string]interface{}{
+			"db": map[string]interface{}{"host": "db.example.com"},
+		}, nil),
+		newReader(t, "app.log", map[string]interface{}{"level": "debug"}, nil),
+	)
+
+	c := conf.New().WithReaders(reader)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+	require.Equal(t, "debug", c.GetString("app.log.level"))
+}
+
+func TestChain_Error(t *testing.T) {
+	t.Parallel()
+
+	reader := conf.Chain(
+		newReader(t, "", map[string]interface{}{"foo": "bar"}, nil),
+		newReader(t, "", nil, errFake),
+		newReader(t, "", nil, errOther),
+	)
+
+	data, err := reader.Read(context.Background())
+	require.ErrorIs(t, err, errFake)
+	require.Nil(t, data)
+}
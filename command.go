@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type command struct {
+	name   string
+	args   []string
+	parser ParseFunc
+	prefix string
+}
+
+func (c *command) Prefix() string {
+	return c.prefix
+}
+
+func (c *command) Read(ctx context.Context) (interface{}, error) {
+	if c.parser == nil {
+		return nil, ErrNoParser
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return c.parser(ctx, &stdout)
+}
+
+func (c *command) WithPrefix(prefix string) Parser {
+	c.prefix = prefix
+	return c
+}
+
+func (c *command) WithParser(parser ParseFunc) Parser {
+	c.parser = parser
+	return c
+}
+
+// NewCommandReader creates an instance of the Parser to read the output of a given command
+// The command is executed on every `Load` call using the given context
+// and the stdout is parsed by the configured parser.
+// A non-zero exit code is returned as an error including the stderr output.
+func NewCommandReader(name string, args ...string) Parser {
+	return &command{
+		name: name,
+		args: args,
+	}
+}
@@ -0,0 +1,35 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestCommandReader(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewCommandReader("echo", "foo:1;bar:2").WithParser(testParseFunc).WithPrefix("pr"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("pr.foo"))
+	require.Equal(t, 2, c.GetInt("pr.bar"))
+}
+
+func TestCommandReader_ErrNoParser(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewCommandReader("echo", "foo:1;bar:2"))
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrNoParser)
+}
+
+func TestCommandReader_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewCommandReader("sh", "-c", "echo something went wrong >&2; exit 3").WithParser(testParseFunc),
+	)
+	require.EqualError(t, c.Load(context.Background()), "reader *conf.command: exit status 3: something went wrong")
+}
@@ -3,42 +3,102 @@ package conf
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
-	"unsafe"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cast"
 )
 
-// Conf is a registry interface
-type Conf interface {
-	// WithReaders stores the given readers to load the data in the Load function
-	WithReaders(readers ...Reader) Conf
-	// WithTransformers stores the given transformers to change the output of the Get function.
-	// All transformers will be applied in the given order.
-	WithTransformers(transformers ...Transform) Conf
+var (
+	// ErrInvalidValue is an error returned if a value cannot be cast to the requested type
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrOverflow is an error returned if a value exceeds the range of the requested type
+	ErrOverflow = errors.New("value out of range")
+	// ErrMissingKeys is an error returned by the Load function if any of the required keys is not set
+	ErrMissingKeys = errors.New("missing required keys")
+	// ErrKeyNotFound is an error returned if a given key is not set
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrNoCertificates is an error returned by the GetCertPool function if the values contain no certificates
+	ErrNoCertificates = errors.New("no certificates found")
+	// ErrKeyCollision is an error returned by the Load function if a scalar value is stored under a prefix of other keys
+	ErrKeyCollision = errors.New("key collision")
+)
 
-	// Reset creates an empty storage and clears the old one
-	// It does not clear the default values
-	// Can be used in the Unit Tests
-	Reset() Conf
-	// Load calls the `Read` function of all readers  in provided order
-	Load(ctx context.Context) error
+// ImmutableConf is a read-only registry interface
+// It exposes the methods to read the values without any method to change them.
+type ImmutableConf interface {
 	// Keys returns the list of the stored keys
 	Keys() []string
-	// SetDefault sets a default value for a key
-	SetDefault(key string, value interface{}) Conf
-	// Set overrides the current value of a given key.
-	Set(key string, value interface{}) Conf
+	// Walk calls a given function for each key and its value in lexical order of the keys
+	// The iteration stops if the function returns false.
+	Walk(fn func(key string, value interface{}) bool)
+	// Dump writes all keys with their values and sources in lexical order of the keys to a given writer
+	Dump(w io.Writer)
+	// Generation returns the number of the successful calls of the Load function
+	Generation() uint64
+	// Checksum returns a stable hash of all keys and their values, including the defaults
+	Checksum() string
+	// OrderedKeys returns the list of the stored keys under a given prefix in order of storing
+	OrderedKeys(prefix string) []string
+	// AllSettings returns the nested structure of all values, including the defaults
+	AllSettings() map[string]interface{}
+	// GetAll returns a copy of all flattened keys and their values, including the defaults
+	GetAll() map[string]interface{}
+	// GetMergedMap deeply merges the nested structures of the values under the given prefixes
+	// The values of the later prefixes take precedence.
+	GetMergedMap(prefixes ...string) map[string]interface{}
+	// FlatStringMap returns the values of all leaf keys under a given prefix cast to String
+	// The keys of the map are relative to the prefix.
+	FlatStringMap(prefix string) map[string]string
+	// GetObject decodes the nested structure of the values under a given key into a given target
+	GetObject(key string, target interface{}) error
+	// UnmarshalMap decodes each named subtree under a given prefix into an item of a given map
+	// The target must be a pointer to a map with string keys.
+	UnmarshalMap(prefix string, target interface{}) error
 	// Get returns a value for a given key if it is set or default value
 	// Returns `nil` if key not found
-
 	Get(key string) interface{}
+	// GetE returns a value for a given key like the Get function and the error of the failed TransformE
+	GetE(key string) (interface{}, error)
+	// GetWithSource returns a value for a given key and the source of the value
+	// The source is a reader, SourceEnv, SourceSet, SourceProfileDefault, SourceDefault or empty if key not found.
+	GetWithSource(key string) (value interface{}, source string)
 	// GetString casts a value for a given key to String
 	GetString(key string) string
+	// GetInterpolated casts a value for a given key to String and replaces the `${key}` references
+	// with the values of the referenced keys
+	GetInterpolated(key string) string
+	// GetRune returns the first character of a string value or casts a number as a code point for a given key
+	GetRune(key string) rune
+	// GetRuneE returns the first character of a string value or casts a number as a code point for a given key
+	// Returns an error if the string is empty or the value is not a valid character
+	GetRuneE(key string) (rune, error)
+	// GetByte returns the first character of a string value or casts a number for a given key to Byte
+	GetByte(key string) byte
+	// GetByteE returns the first character of a string value or casts a number for a given key to Byte
+	// Returns an error if the string is empty or the value is not a valid character or does not fit Byte
+	GetByteE(key string) (byte, error)
 	// GetInt casts a value for a given key to Int
 	GetInt(key string) int
 	// GetInt8 casts a value for a given key to Int8
@@ -49,46 +109,344 @@ type Conf interface {
 	GetInt32(key string) int32
 	// GetInt64 casts a value for a given key to Int64
 	GetInt64(key string) int64
+	// GetIntE casts a value for a given key to Int
+	// Returns an error if the value cannot be cast to Int or exceeds its range
+	GetIntE(key string) (int, error)
+	// GetInt8E casts a value for a given key to Int8
+	// Returns an error if the value cannot be cast to Int8 or exceeds its range
+	GetInt8E(key string) (int8, error)
+	// GetInt16E casts a value for a given key to Int16
+	// Returns an error if the value cannot be cast to Int16 or exceeds its range
+	GetInt16E(key string) (int16, error)
+	// GetInt32E casts a value for a given key to Int32
+	// Returns an error if the value cannot be cast to Int32 or exceeds its range
+	GetInt32E(key string) (int32, error)
+	// GetInt64E casts a value for a given key to Int64
+	// Returns an error if the value cannot be cast to Int64
+	GetInt64E(key string) (int64, error)
 	// GetBool casts a value for a given key to Bool
 	GetBool(key string) bool
+	// GetBoolE casts a value for a given key to Bool
+	// Returns an error if the value cannot be recognized as Bool
+	GetBoolE(key string) (bool, error)
+	// GetStringMapBool casts the values under a given key to a map of Bool
+	GetStringMapBool(key string) map[string]bool
+	// GetStringMapString casts the values under a given key to a map of String
+	GetStringMapString(key string) map[string]string
 	// GetFloat32 casts a value for a given key to Float32
 	GetFloat32(key string) float32
 	// GetFloat64 casts a value for a given key to Float64
 	GetFloat64(key string) float64
+	// GetPercent casts a value for a given key to a ratio, so `"10%"` and `0.1` give `0.1`
+	GetPercent(key string) float64
+	// GetComplex64 casts a value for a given key to Complex64
+	GetComplex64(key string) complex64
+	// GetComplex64E casts a value for a given key to Complex64
+	// Returns an error if the value cannot be cast to Complex64
+	GetComplex64E(key string) (complex64, error)
+	// GetComplex128 casts a value for a given key to Complex128
+	GetComplex128(key string) complex128
+	// GetComplex128E casts a value for a given key to Complex128
+	// Returns an error if the value cannot be cast to Complex128
+	GetComplex128E(key string) (complex128, error)
 	// GetTime casts a value for a given key to `time.Time`
 	GetTime(key string) time.Time
+	// GetTimeUnixMilli casts a value for a given key to `time.Time` treating the numbers as Unix milliseconds
+	GetTimeUnixMilli(key string) time.Time
+	// GetTimeUnixNano casts a value for a given key to `time.Time` treating the numbers as Unix nanoseconds
+	GetTimeUnixNano(key string) time.Time
 	// GetDuration casts a value for a given key to `time.Duration`
 	GetDuration(key string) time.Duration
+	// GetStringD casts a value for a given key to String
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetStringD(key, def string) string
+	// GetIntD casts a value for a given key to Int
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetIntD(key string, def int) int
+	// GetInt8D casts a value for a given key to Int8
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetInt8D(key string, def int8) int8
+	// GetInt16D casts a value for a given key to Int16
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetInt16D(key string, def int16) int16
+	// GetInt32D casts a value for a given key to Int32
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetInt32D(key string, def int32) int32
+	// GetInt64D casts a value for a given key to Int64
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetInt64D(key string, def int64) int64
+	// GetBoolD casts a value for a given key to Bool
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetBoolD(key string, def bool) bool
+	// GetFloat32D casts a value for a given key to Float32
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetFloat32D(key string, def float32) float32
+	// GetFloat64D casts a value for a given key to Float64
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetFloat64D(key string, def float64) float64
+	// GetComplex64D casts a value for a given key to Complex64
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetComplex64D(key string, def complex64) complex64
+	// GetComplex128D casts a value for a given key to Complex128
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetComplex128D(key string, def complex128) complex128
+	// GetTimeD casts a value for a given key to `time.Time`
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetTimeD(key string, def time.Time) time.Time
+	// GetDurationD casts a value for a given key to `time.Duration`
+	// Returns a given default value if the key is not found or the value cannot be cast
+	GetDurationD(key string, def time.Duration) time.Duration
+	// GetStringMapStringD casts the values under a given key to a map of String merged over a given default map
+	GetStringMapStringD(key string, def map[string]string) map[string]string
+	// GetStringPtr casts a value for a given key to String and returns a pointer to it
+	// Returns `nil` if the key is not found or the value cannot be cast
+	GetStringPtr(key string) *string
+	// GetIntPtr casts a value for a given key to Int and returns a pointer to it
+	// Returns `nil` if the key is not found or the value cannot be cast
+	GetIntPtr(key string) *int
+	// GetBoolPtr casts a value for a given key to Bool and returns a pointer to it
+	// Returns `nil` if the key is not found or the value cannot be cast
+	GetBoolPtr(key string) *bool
+	// GetByteSize parses a value for a given key as a size, e.g. `1.5GB`, and returns the number of bytes and the unit
+	// Returns an error if the value cannot be parsed or is negative
+	GetByteSize(key string) (value uint64, unit string, err error)
+	// GetNumberWithUnit parses a value for a given key as a number with a unit, e.g. `5m`,
+	// and returns the number multiplied by the multiplier of the unit from a given table
+	GetNumberWithUnit(key string, units map[string]float64) (float64, error)
+	// GetStringSlice casts a value for a given key to a slice of strings
+	// The strings are split by the list separator, if present, or by the white spaces.
+	GetStringSlice(key string) []string
+	// GetStringSliceUnique casts a value for a given key to a slice of strings without duplicates
+	// The first occurrence of each string is kept.
+	GetStringSliceUnique(key string) []string
+	// GetIntSlice casts a value for a given key to a slice of Int casting each item separately
+	GetIntSlice(key string) []int
+	// GetLines splits a string value for a given key into the trimmed lines without the blank ones
+	GetLines(key string) []string
+	// GetFileContent reads the file by the path stored as a value for a given key
+	GetFileContent(key string) ([]byte, error)
+	// GetCertificate loads a certificate and a private key from the values for the given keys
+	// The values are the inline PEM data or the paths to the PEM files.
+	GetCertificate(certKey, keyKey string) (tls.Certificate, error)
+	// GetCertPool loads the certificates from the values for a given key into a pool
+	// The values are the inline PEM data or the paths to the PEM files.
+	GetCertPool(key string) (*x509.CertPool, error)
+	// GetMapSlice casts a value for a given key to a slice of maps
+	// Returns `nil` if the value is not a slice or any of its items is not a map
+	GetMapSlice(key string) []map[string]interface{}
+}
+
+// Conf is a registry interface
+type Conf interface {
+	// ImmutableConf provides the read-only methods
+	ImmutableConf
+
+	// WithReaders stores the given readers to load the data in the Load function
+	WithReaders(readers ...Reader) Conf
+	// AddReader appends the given reader to the list of the readers
+	// The values of the appended reader take precedence over the values of the existing readers.
+	AddReader(reader Reader) Conf
+	// PrependReader inserts the given reader before all existing readers
+	// The values of the existing readers take precedence over the values of the prepended reader.
+	PrependReader(reader Reader) Conf
+	// Readers returns a copy of the list of the readers in the order of loading
+	Readers() []Reader
+	// WithTransformers stores the given transformers to change the output of the Get function.
+	// All transformers will be applied in the given order.
+	WithTransformers(transformers ...Transform) Conf
+	// InsertTransformer inserts the given transformer at the given position of the transformers chain
+	// The index is clamped to the bounds of the chain.
+	InsertTransformer(index int, transformer Transform) Conf
+	// Transformers returns a copy of the transformers chain in the order of applying
+	Transformers() []Transform
+	// WithTransformersE stores the given error-returning transformers applied after the regular transformers
+	// The GetE function returns the errors, all other getters see `nil` instead of the failed value.
+	WithTransformersE(transformers ...TransformE) Conf
+	// WithRecoverTransformers enables recovering from the panics of the transformers
+	// The panics are logged by the given logger and the Get function returns the untransformed value.
+	WithRecoverTransformers(logger *slog.Logger) Conf
+	// WithLoadTransformers stores the given functions to change the raw data of each reader in the Load function
+	// All functions will be applied in the given order before storing the data.
+	WithLoadTransformers(fns ...func(raw interface{}) interface{}) Conf
+
+	// WithReadOptimized enables a read-only snapshot of the storage used by the Get function
+	// The snapshot is rebuilt on every change, so it should be used for read-heavy workloads only.
+	WithReadOptimized() Conf
+
+	// Reset creates an empty storage and clears the old one
+	// It does not clear the default values
+	// Can be used in the Unit Tests
+	Reset() Conf
+	// ResetPrefix deletes the stored values of a given key and all keys under it
+	// It does not clear the default values
+	ResetPrefix(prefix string) Conf
+	// Load calls the `Read` function of all readers  in provided order
+	Load(ctx context.Context) error
+	// LoadDryRun calls the `Read` function of all readers in provided order
+	// and returns the difference between the stored values and the loaded ones without applying them.
+	LoadDryRun(ctx context.Context) (Diff, error)
+	// LoadDefaults calls the `Read` function of given readers in provided order and stores the values as defaults
+	LoadDefaults(ctx context.Context, readers ...Reader) error
+	// WithCollectLoadErrors makes the Load function read all readers despite the failures
+	// The errors of all failed readers are returned joined.
+	WithCollectLoadErrors() Conf
+	// WithIncrementalLoad makes the Load function update only the changed keys instead of replacing all values
+	WithIncrementalLoad() Conf
+	// WithRequiredKeys stores the keys that must be set after loading all readers
+	// The Load function returns an error listing the missing keys.
+	WithRequiredKeys(keys ...string) Conf
+	// WithRawKeys makes the Load function store the data under the given keys as is, without flattening
+	WithRawKeys(keys ...string) Conf
+	// WithCollisionCheck makes the Load function report the scalar values stored under a prefix of other keys
+	WithCollisionCheck() Conf
+	// WithAtomicSlices makes the Load function store the slices as single values without the indexed keys
+	WithAtomicSlices() Conf
+	// WithKeyNormalizer sets a function to convert the keys into a canonical form
+	// The function is applied to the keys on storing the values and on looking them up.
+	WithKeyNormalizer(fn func(key string) string) Conf
+	// Ready checks the sources of all readers implementing the HealthChecker interface in provided order
+	// Returns the first error
+	Ready(ctx context.Context) error
+	// OnSecretRotation registers a function to be called with the new value of a given secret key
+	// The function is called by the Load function if the stored value of the key has been changed.
+	OnSecretRotation(key string, fn func(newValue interface{})) Conf
+	// OnChange registers a function to be called for each key added, removed or changed by the Load function
+	OnChange(fn func(key string, change Change)) Conf
+	// WithConflictLogger sets a logger to report the keys that change from a scalar to a container or vice versa
+	// during the Load function
+	WithConflictLogger(logger *slog.Logger) Conf
+	// SetDefault sets a default value for a key
+	SetDefault(key string, value interface{}) Conf
+	// SetDefaultFromEnv sets a default value for a key to be read from a given environment variable
+	// A given fallback value is used if the variable is not set.
+	SetDefaultFromEnv(key, envVar string, fallback interface{}) Conf
+	// WithAutomaticEnv makes the getters check the environment variable derived from a key before other values
+	// The name of the variable is a given prefix followed by the uppercased key with underscores
+	// instead of the dots and the dashes, the prefix is used as is like by NewEnvReader.
+	WithAutomaticEnv(prefix string) Conf
+	// WithProfile uses the value of a given key as the name of the active profile
+	// The profile defaults take precedence over the generic defaults.
+	WithProfile(key string) Conf
+	// SetProfileDefault sets a default value for a key in a given profile
+	SetProfileDefault(profile, key string, value interface{}) Conf
+	// Set overrides the current value of a given key.
+	Set(key string, value interface{}) Conf
+	// WithBoolValues sets the string values that should be converted as true or false by this instance
+	// The given values are checked before the global BoolValues.
+	WithBoolValues(values map[string]bool) Conf
+	// WithDecimalSeparator sets the decimal separator of the string values
+	// used by the GetFloat32 and GetFloat64 functions
+	WithDecimalSeparator(sep rune) Conf
+	// WithDurationUnit sets the unit of the numbers and the numeric strings used by the GetDuration function
+	WithDurationUnit(unit time.Duration) Conf
+	// WithListSeparator sets the separator of the string values used by the GetStringSlice function
+	WithListSeparator(sep rune) Conf
+	// WithStrictCast enables reporting of the cast failures of the getters without error
+	// The failures are logged by the given logger or cause a panic if the logger is nil.
+	WithStrictCast(logger *slog.Logger) Conf
+	// Immutable returns a read-only view of the configuration
+	// The view does not expose the methods to change the configuration, e.g. Set, Load or Reset.
+	Immutable() ImmutableConf
 }
 
 type conf struct {
-	storage  *sync.Map
+	mu       sync.RWMutex
+	loadMu   sync.Mutex
+	current  atomic.Pointer[sync.Map]
 	defaults *sync.Map
-
-	readers      []Reader
-	transformers []Transform
+	profiles *sync.Map
+
+	readers          []Reader
+	transformers     []Transform
+	transformersE    []TransformE
+	loadTransformers []func(raw interface{}) interface{}
+	profileKey       string
+	recoverLog       *slog.Logger
+
+	conflictLogger   *slog.Logger
+	boolValues       map[string]bool
+	requiredKeys     []string
+	collectErrors    bool
+	collisionCheck   bool
+	rawKeys          []string
+	atomicSlices     bool
+	keyNormalizer    func(key string) string
+	rotations        []rotation
+	changeHandlers   []func(key string, change Change)
+	incremental      bool
+	strictCast       bool
+	strictLogger     *slog.Logger
+	decimalSeparator rune
+	durationUnit     time.Duration
+	automaticEnv     bool
+	envPrefix        string
+	listSeparator    rune
+
+	order      keyOrder
+	generation atomic.Uint64
+
+	readOptimized bool
+	snapshot      atomic.Pointer[map[string]interface{}]
+	snapshotMu    sync.Mutex
 }
 
 // New crates an instance of Conf interface
 func New() Conf {
 	c := &conf{
-		storage:  &sync.Map{},
 		defaults: &sync.Map{},
+		profiles: &sync.Map{},
 	}
+	c.current.Store(&sync.Map{})
 	return c
 }
 
-var globalConf = New()
+var globalConf atomic.Pointer[Conf]
+
+func init() {
+	SetGlobalConf(nil)
+}
 
 // GlobalConf returns the global Conf object
 func GlobalConf() Conf {
-	return globalConf
+	return *globalConf.Load()
+}
+
+// SetGlobalConf atomically replaces the global Conf object
+// A new instance is created if the given object is nil.
+// Can be used in the Unit Tests to avoid sharing the global state between the tests.
+func SetGlobalConf(c Conf) {
+	if c == nil {
+		c = New()
+	}
+	globalConf.Store(&c)
+}
+
+// Immutable returns a read-only view of the configuration
+// The view reads the same values, so the changes made by the owner of the configuration are visible,
+// but it cannot be used to change them, so it can be passed to the subsystems that must not do it.
+// The alias to work with an instance of the global configuration manager.
+func Immutable() ImmutableConf {
+	return GlobalConf().Immutable()
+}
+
+func (c *conf) Immutable() ImmutableConf {
+	return immutable{c}
+}
+
+// immutable hides the mutating methods of Conf, so the view cannot be cast back to Conf
+type immutable struct {
+	ImmutableConf
+}
+
+// MarshalJSON encodes the result of the AllSettings function
+func (i immutable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.AllSettings())
 }
 
 // WithReaders overrides the readers with the given ones
 // The alias to work with an instance of the global configuration manager.
 func WithReaders(readers ...Reader) Conf {
-	return globalConf.WithReaders(readers...)
+	return GlobalConf().WithReaders(readers...)
 }
 
 func (c *conf) WithReaders(readers ...Reader) Conf {
@@ -96,11 +454,45 @@ func (c *conf) WithReaders(readers ...Reader) Conf {
 	return c
 }
 
+// AddReader appends the given reader to the list of the readers
+// The values of the appended reader take precedence over the values of the existing readers.
+// The alias to work with an instance of the global configuration manager.
+func AddReader(reader Reader) Conf {
+	return GlobalConf().AddReader(reader)
+}
+
+func (c *conf) AddReader(reader Reader) Conf {
+	c.readers = append(slices.Clip(c.readers), reader)
+	return c
+}
+
+// PrependReader inserts the given reader before all existing readers
+// The values of the existing readers take precedence over the values of the prepended reader.
+// The alias to work with an instance of the global configuration manager.
+func PrependReader(reader Reader) Conf {
+	return GlobalConf().PrependReader(reader)
+}
+
+func (c *conf) PrependReader(reader Reader) Conf {
+	c.readers = append([]Reader{reader}, c.readers...)
+	return c
+}
+
+// Readers returns a copy of the list of the readers in the order of loading
+// The alias to work with an instance of the global configuration manager.
+func Readers() []Reader {
+	return GlobalConf().Readers()
+}
+
+func (c *conf) Readers() []Reader {
+	return slices.Clone(c.readers)
+}
+
 // WithTransformers stores the given transformers to change the output of the Get function
 // All transformers will be applied in the given order.
 // The alias to work with an instance of the global configuration manager.
 func WithTransformers(transformers ...Transform) Conf {
-	return globalConf.WithTransformers(transformers...)
+	return GlobalConf().WithTransformers(transformers...)
 }
 
 func (c *conf) WithTransformers(transformers ...Transform) Conf {
@@ -108,20 +500,94 @@ func (c *conf) WithTransformers(transformers ...Transform) Conf {
 	return c
 }
 
+// WithTransformersE stores the given error-returning transformers applied after the regular transformers
+// The chain stops on the first error, the GetE function returns it, and all other getters, including Get,
+// see `nil` instead of the failed value, so they return the zero values or the given defaults.
+// All transformers will be applied in the given order.
+// The alias to work with an instance of the global configuration manager.
+func WithTransformersE(transformers ...TransformE) Conf {
+	return GlobalConf().WithTransformersE(transformers...)
+}
+
+func (c *conf) WithTransformersE(transformers ...TransformE) Conf {
+	c.transformersE = transformers
+	return c
+}
+
+// WithLoadTransformers stores the given functions to change the raw data of each reader in the Load function
+// Unlike the transformers applied by the Get function to each value, the functions are applied once per loading
+// to the whole data returned by a reader before flattening, so they can rewrite the structure of the data,
+// e.g. rename the top level keys or normalize the schema of a source.
+// All functions will be applied in the given order.
+// The alias to work with an instance of the global configuration manager.
+func WithLoadTransformers(fns ...func(raw interface{}) interface{}) Conf {
+	return GlobalConf().WithLoadTransformers(fns...)
+}
+
+func (c *conf) WithLoadTransformers(fns ...func(raw interface{}) interface{}) Conf {
+	c.loadTransformers = fns
+	return c
+}
+
+// InsertTransformer inserts the given transformer at the given position of the transformers chain
+// The index is clamped to the bounds of the chain.
+// The alias to work with an instance of the global configuration manager.
+func InsertTransformer(index int, transformer Transform) Conf {
+	return GlobalConf().InsertTransformer(index, transformer)
+}
+
+func (c *conf) InsertTransformer(index int, transformer Transform) Conf {
+	index = max(0, min(index, len(c.transformers)))
+	c.transformers = slices.Insert(slices.Clone(c.transformers), index, transformer)
+	return c
+}
+
+// Transformers returns a copy of the transformers chain in the order of applying
+// The alias to work with an instance of the global configuration manager.
+func Transformers() []Transform {
+	return GlobalConf().Transformers()
+}
+
+func (c *conf) Transformers() []Transform {
+	return slices.Clone(c.transformers)
+}
+
+// WithRecoverTransformers enables recovering from the panics of the transformers
+// The panics are logged by the given logger or by the default logger if nil,
+// and the Get function returns the untransformed value, so a buggy transformer does not crash the application.
+// By default the panics are propagated.
+// The alias to work with an instance of the global configuration manager.
+func WithRecoverTransformers(logger *slog.Logger) Conf {
+	return GlobalConf().WithRecoverTransformers(logger)
+}
+
+func (c *conf) WithRecoverTransformers(logger *slog.Logger) Conf {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	c.recoverLog = logger
+	return c
+}
+
 // Reset creates an empty storage and clears the old one
 // It does not clear the default values
 // Can be used in the Unit Tests
 // The alias to work with an instance of the global configuration manager.
 func Reset() Conf {
-	return globalConf.Reset()
+	return GlobalConf().Reset()
 }
 
 func (c *conf) Reset() Conf {
-	old := atomic.SwapPointer(
-		(*unsafe.Pointer)(unsafe.Pointer(&c.storage)), //nolint:gosec
-		unsafe.Pointer(&sync.Map{}),                   //nolint:gosec
-	)
-	s := (*sync.Map)(old)
+	c.mu.Lock()
+	c.reset()
+	c.mu.Unlock()
+	c.refreshSnapshot()
+
+	return c
+}
+
+func (c *conf) reset() {
+	s := c.current.Swap(&sync.Map{})
 
 	var keys []interface{}
 	s.Range(func(key, value interface{}) bool {
@@ -131,250 +597,2577 @@ func (c *conf) Reset() Conf {
 	for _, key := range keys {
 		s.Delete(key)
 	}
+	c.order.reset()
+}
+
+// ResetPrefix deletes the stored values of a given key and all keys under it
+// It does not clear the default values
+// Can be used to reload the configuration of a single subsystem.
+// The alias to work with an instance of the global configuration manager.
+func ResetPrefix(prefix string) Conf {
+	return GlobalConf().ResetPrefix(prefix)
+}
+
+func (c *conf) ResetPrefix(prefix string) Conf {
+	prefix = c.normalizeKey(prefix)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.storage()
+
+	var keys []string
+	s.Range(func(key, value interface{}) bool {
+		if k := key.(string); k == prefix || strings.HasPrefix(k, prefix+".") {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	for _, key := range keys {
+		s.Delete(key)
+	}
+	c.order.delete(keys...)
+	c.refreshSnapshot()
+
+	return c
+}
 
+// storage returns the current storage of the values
+func (c *conf) storage() *sync.Map {
+	return c.current.Load()
+}
+
+// WithReadOptimized enables a read-only snapshot of the storage used by the Get function
+// The snapshot is a plain map rebuilt on every change (copy-on-write), so it should be used
+// for read-heavy workloads only, when the values are changed by the Load function mostly.
+// The alias to work with an instance of the global configuration manager.
+func WithReadOptimized() Conf {
+	return GlobalConf().WithReadOptimized()
+}
+
+func (c *conf) WithReadOptimized() Conf {
+	c.readOptimized = true
+	c.refreshSnapshot()
 	return c
 }
 
+func (c *conf) refreshSnapshot() {
+	if !c.readOptimized {
+		return
+	}
+
+	c.snapshotMu.Lock()
+	defer c.snapshotMu.Unlock()
+
+	snapshot := map[string]interface{}{}
+	c.storage().Range(func(key, value interface{}) bool {
+		snapshot[key.(string)] = value
+		return true
+	})
+	c.snapshot.Store(&snapshot)
+}
+
 // Load calls the `Read` function of all readers  in provided order
+//
+// The data of all readers is flattened into the same key space and the last writer wins per exact key.
+// If a key holds a scalar in one reader and a container (map or slice) in another,
+// the value of the last reader is stored and the child keys of the container are kept,
+// see WithConflictLogger to report such collisions.
+//
+// The error of a reader is returned as LoadError and prefixed by the name of the reader,
+// if the reader implements the Named interface, or by its type.
+// The loading stops on the first error, see WithCollectLoadErrors to get the errors of all readers.
+// The context is checked before each reader, so the error of a cancelled context is returned
+// without calling the remaining readers.
+// All stored values are replaced, see WithIncrementalLoad to update only the changed keys
+// and OnChange to be notified about the changes.
+// The readers and the load transformers are called without locking the configuration,
+// so they can read the values stored by the previous Load.
+//
 // The alias to work with an instance of the global configuration manager.
 func Load(ctx context.Context) error {
-	return globalConf.Load(ctx)
+	return GlobalConf().Load(ctx)
 }
 
 func (c *conf) Load(ctx context.Context) error {
-	c.Reset()
+	// the loads are serialized, so the data of an older load never replaces the data of a newer one,
+	// but the readers and the load transformers run without the lock, so they can read the configuration
+	c.loadMu.Lock()
+	storage := &sync.Map{}
+	order := &keyOrder{}
+	err := c.readAll(ctx, c.readers, storage, order)
+
+	c.mu.Lock()
+	secrets := c.secrets()
+	var changes Diff
+	if c.incremental {
+		if err == nil {
+			changes = c.apply(storage, order)
+		}
+	} else {
+		old := c.current.Swap(storage)
+		c.order.replace(order)
+		if len(c.changeHandlers) > 0 {
+			changes = diff(old, storage)
+		}
+	}
+	c.mu.Unlock()
+	c.refreshSnapshot()
+	c.loadMu.Unlock()
+
+	// the values of the succeeded readers are stored even if the loading fails, so the changes are reported anyway
+	c.rotate(secrets)
+	c.notify(changes)
+	if err != nil {
+		return err
+	}
 
-	for _, reader := range c.readers {
-		data, err := reader.Read(ctx)
-		if err != nil {
-			return err
+	if c.collisionCheck {
+		if keys := c.collisions(); len(keys) > 0 {
+			return fmt.Errorf("%w: %s", ErrKeyCollision, strings.Join(keys, ", "))
 		}
+	}
 
-		c.scan(data, reader.Prefix())
+	var missing []string
+	for _, key := range c.requiredKeys {
+		if _, ok := c.lookup(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingKeys, strings.Join(missing, ", "))
 	}
 
+	c.generation.Add(1)
+
 	return nil
 }
 
-func (c *conf) scan(data interface{}, key string) {
-	if key != "" {
-		c.storage.Store(key, data)
-		key += "."
+// rotation is a function to be called on changing the value of a secret key
+type rotation struct {
+	key string
+	fn  func(newValue interface{})
+}
+
+// OnSecretRotation registers a function to be called with the new value of a given secret key
+// The function is called by the Load function if the stored value of the key has been changed,
+// so the first loading of the key and its removal are not reported.
+// The new value is returned by the Get function, so the transformers are applied.
+// The alias to work with an instance of the global configuration manager.
+func OnSecretRotation(key string, fn func(newValue interface{})) Conf {
+	return GlobalConf().OnSecretRotation(key, fn)
+}
+
+func (c *conf) OnSecretRotation(key string, fn func(newValue interface{})) Conf {
+	c.rotations = append(c.rotations, rotation{key: key, fn: fn})
+	return c
+}
+
+// secrets returns the stored values of the secret keys
+func (c *conf) secrets() map[string]interface{} {
+	if len(c.rotations) == 0 {
+		return nil
 	}
 
-	v := reflect.ValueOf(data)
+	res := make(map[string]interface{}, len(c.rotations))
+	for _, r := range c.rotations {
+		if value, ok := c.storage().Load(c.normalizeKey(r.key)); ok {
+			res[r.key] = value
+		}
+	}
 
-	switch v.Kind() { //nolint:exhaustive // We don't need to check all types
-	case reflect.Map:
-		iter := v.MapRange()
-		for iter.Next() {
-			c.scan(iter.Value().Interface(), key+iter.Key().String())
+	return res
+}
+
+// rotate calls the rotation functions of the secret keys changed since a given state
+func (c *conf) rotate(secrets map[string]interface{}) {
+	for _, r := range c.rotations {
+		old, ok := secrets[r.key]
+		if !ok {
+			continue
 		}
-	case reflect.Array, reflect.Slice:
-		for i := 0; i < v.Len(); i++ {
-			c.scan(v.Index(i).Interface(), key+strconv.Itoa(i))
+
+		value, ok := c.storage().Load(c.normalizeKey(r.key))
+		if ok && !reflect.DeepEqual(old, value) {
+			r.fn(c.Get(r.key))
 		}
-	default:
 	}
 }
 
-// Keys returns the list of the stored keys
-// The alias to work with an instance of the global configuration manager.
-func Keys() []string {
-	return globalConf.Keys()
-}
+// readAll calls the `Read` function of given readers and stores the flattened data into a given storage
+// The order of the keys is tracked if the order is not nil.
+// The errors are returned as LoadError, all of them are joined if the collecting is enabled.
+func (c *conf) readAll(ctx context.Context, readers []Reader, storage *sync.Map, order *keyOrder) error {
+	var errs []error
+	for i, reader := range readers {
+		// stop between the readers, even if a reader ignores the context
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
 
-func (c *conf) Keys() []string {
-	var keys []string
+		data, err := reader.Read(ctx)
+		if errors.Is(err, errSkipped) {
+			continue
+		}
+		if err != nil {
+			err = &LoadError{Index: i, Name: readerName(reader), Reader: reader, Err: err}
+			if !c.collectErrors {
+				return err
+			}
+			errs = append(errs, err)
+			continue
+		}
 
-	c.storage.Range(func(key, value interface{}) bool {
-		keys = append(keys, key.(string))
-		return true
-	})
+		for _, fn := range c.loadTransformers {
+			data = fn(data)
+		}
 
-	c.defaults.Range(func(key, value interface{}) bool {
-		keys = append(keys, key.(string))
-		return true
-	})
+		c.scan(storage, order, readerSource(i, reader), data, reader.Prefix())
+	}
 
-	return keys
+	return errors.Join(errs...)
 }
 
-// SetDefault sets a default value for a key
+// WithIncrementalLoad makes the Load function update only the changed keys instead of replacing all values
+// The data of all readers is loaded into a separate storage and compared with the stored values,
+// so the unchanged values are kept as is and the storage is never empty during the loading.
+// The values are not changed at all if any reader fails, even if WithCollectLoadErrors is enabled.
 // The alias to work with an instance of the global configuration manager.
-func SetDefault(key string, value interface{}) Conf {
-	return globalConf.SetDefault(key, value)
+func WithIncrementalLoad() Conf {
+	return GlobalConf().WithIncrementalLoad()
 }
 
-func (c *conf) SetDefault(key string, value interface{}) Conf {
-	c.defaults.Store(key, value)
+func (c *conf) WithIncrementalLoad() Conf {
+	c.incremental = true
 	return c
 }
 
-// Set overrides the current value of a given key.
-// The alias to work with an instance of the global configuration manager.
-func Set(key string, value interface{}) Conf {
-	return globalConf.Set(key, value)
-}
+// apply stores the difference between the stored values and the given storage
+func (c *conf) apply(storage *sync.Map, order *keyOrder) Diff {
+	current := c.storage()
+	changes := diff(current, storage)
+	for key := range changes.Removed {
+		current.Delete(key)
+	}
+	for key, value := range changes.Added {
+		current.Store(key, value)
+	}
+	for key, change := range changes.Changed {
+		current.Store(key, change.New)
+	}
+	c.order.replace(order)
 
-func (c *conf) Set(key string, value interface{}) Conf {
-	c.storage.Store(key, value)
-	return c
+	return changes
 }
 
-// Get returns a value for a given key if it is set or default value
-// Returns `nil` if key not found
+// OnChange registers a function to be called for each key added, removed or changed by the Load function
+// The function gets the raw old and new values, the old value of an added key and the new value
+// of a removed key are `nil`. The keys are reported in lexical order after all values are loaded,
+// so the function can read the other keys. The values changed by the Set function are not reported.
+// The changes are reported even if the Load function fails, because the values of the succeeded readers
+// are stored, unless WithIncrementalLoad is enabled.
 // The alias to work with an instance of the global configuration manager.
-func Get(key string) interface{} {
-	return globalConf.Get(key)
+func OnChange(fn func(key string, change Change)) Conf {
+	return GlobalConf().OnChange(fn)
 }
 
-func (c *conf) Get(key string) interface{} {
-	value, ok := c.storage.Load(key)
-	if !ok {
-		value, _ = c.defaults.Load(key)
+func (c *conf) OnChange(fn func(key string, change Change)) Conf {
+	c.changeHandlers = append(c.changeHandlers, fn)
+	return c
+}
+
+// notify calls the change handlers for each key of a given difference in lexical order
+func (c *conf) notify(changes Diff) {
+	if len(c.changeHandlers) == 0 || changes.Empty() {
+		return
 	}
 
-	for _, tr := range c.transformers {
-		value = tr(key, value, c)
+	all := make(map[string]Change, len(changes.Added)+len(changes.Removed)+len(changes.Changed))
+	for key, value := range changes.Added {
+		all[key] = Change{New: value}
 	}
+	for key, value := range changes.Removed {
+		all[key] = Change{Old: value}
+	}
+	maps.Copy(all, changes.Changed)
 
-	return value
+	keys := slices.Sorted(maps.Keys(all))
+	for _, key := range keys {
+		for _, fn := range c.changeHandlers {
+			fn(key, all[key])
+		}
+	}
 }
 
-// GetString casts a value for a given key to String
+// WithCollectLoadErrors makes the Load function read all readers despite the failures
+// The values of the succeeded readers are stored and the errors of all failed readers are returned
+// joined by the `errors.Join` function.
 // The alias to work with an instance of the global configuration manager.
-func GetString(key string) string {
-	return globalConf.GetString(key)
+func WithCollectLoadErrors() Conf {
+	return GlobalConf().WithCollectLoadErrors()
 }
 
-func (c *conf) GetString(key string) string {
-	return cast.ToString(c.Get(key))
+func (c *conf) WithCollectLoadErrors() Conf {
+	c.collectErrors = true
+	return c
 }
 
-// GetInt casts a value for a given key to Int
+// LoadDryRun calls the `Read` function of all readers in provided order
+// and returns the difference between the stored values and the loaded ones without applying them.
+// The defaults are not compared.
 // The alias to work with an instance of the global configuration manager.
-func GetInt(key string) int {
-	return globalConf.GetInt(key)
+func LoadDryRun(ctx context.Context) (Diff, error) {
+	return GlobalConf().LoadDryRun(ctx)
 }
 
-func (c *conf) GetInt(key string) int {
-	return cast.ToInt(c.Get(key))
+func (c *conf) LoadDryRun(ctx context.Context) (Diff, error) {
+	storage := &sync.Map{}
+	if err := c.readAll(ctx, c.readers, storage, nil); err != nil {
+		return Diff{}, err
+	}
+
+	return diff(c.storage(), storage), nil
 }
 
-// GetInt8 casts a value for a given key to Int8
+// LoadDefaults calls the `Read` function of given readers in provided order and stores the values as defaults
+// The baseline configuration can be read from a file or any other source this way, the readers are not
+// added to the list of the readers of the Load function and the values are kept by the Reset function.
+// The loaded values override the defaults of the same keys, the other defaults are kept.
+// Nothing is stored if any reader fails, see Load for the details of the errors.
 // The alias to work with an instance of the global configuration manager.
-func GetInt8(key string) int8 {
-	return globalConf.GetInt8(key)
+func LoadDefaults(ctx context.Context, readers ...Reader) error {
+	return GlobalConf().LoadDefaults(ctx, readers...)
 }
 
-func (c *conf) GetInt8(key string) int8 {
-	return cast.ToInt8(c.Get(key))
+func (c *conf) LoadDefaults(ctx context.Context, readers ...Reader) error {
+	storage := &sync.Map{}
+	if err := c.readAll(ctx, readers, storage, nil); err != nil {
+		return err
+	}
+
+	storage.Range(func(key, value interface{}) bool {
+		c.defaults.Store(key, value)
+		return true
+	})
+
+	return nil
 }
 
-// GetInt16 casts a value for a given key to Int16
+// WithRequiredKeys stores the keys that must be set after loading all readers
+// The Load function returns an error listing the missing keys.
+// The defaults satisfy the requirement.
 // The alias to work with an instance of the global configuration manager.
-func GetInt16(key string) int16 {
-	return globalConf.GetInt16(key)
+func WithRequiredKeys(keys ...string) Conf {
+	return GlobalConf().WithRequiredKeys(keys...)
 }
 
-func (c *conf) GetInt16(key string) int16 {
-	return cast.ToInt16(c.Get(key))
+func (c *conf) WithRequiredKeys(keys ...string) Conf {
+	c.requiredKeys = keys
+	return c
 }
 
-// GetInt32 casts a value for a given key to Int32
+// WithRawKeys makes the Load function store the data under the given keys as is, without flattening
+// The nested structure is returned by the Get function for the key, but the child keys are not stored,
+// so it is useful for the subsystems expecting the original data, e.g. a reader with a given prefix.
 // The alias to work with an instance of the global configuration manager.
-func GetInt32(key string) int32 {
-	return globalConf.GetInt32(key)
+func WithRawKeys(keys ...string) Conf {
+	return GlobalConf().WithRawKeys(keys...)
 }
 
-func (c *conf) GetInt32(key string) int32 {
-	return cast.ToInt32(c.Get(key))
+func (c *conf) WithRawKeys(keys ...string) Conf {
+	c.rawKeys = keys
+	return c
 }
 
-// GetInt64 casts a value for a given key to Int64
+// WithCollisionCheck makes the Load function report the scalar values stored under a prefix of other keys
+// Such values appear if a reader with a prefix, e.g. `db`, returns a scalar and another reader
+// returns a value for a key under the same prefix, e.g. `db.host`, so both of them are stored.
+// The Load function returns an error listing the colliding keys, the values are loaded anyway.
 // The alias to work with an instance of the global configuration manager.
-func GetInt64(key string) int64 {
-	return globalConf.GetInt64(key)
+func WithCollisionCheck() Conf {
+	return GlobalConf().WithCollisionCheck()
 }
 
-func (c *conf) GetInt64(key string) int64 {
-	return cast.ToInt64(c.Get(key))
+func (c *conf) WithCollisionCheck() Conf {
+	c.collisionCheck = true
+	return c
 }
 
-// BoolValues is a global extendable list of the string values that should be converted as true or false
-//
+// WithAtomicSlices makes the Load function store the slices as single values without the indexed keys
+// The Get function returns the whole slice, but the keys like `hosts.0` are neither stored nor returned
+// by the Keys function, so the items cannot be accessed by their indexes.
+// The alias to work with an instance of the global configuration manager.
+func WithAtomicSlices() Conf {
+	return GlobalConf().WithAtomicSlices()
+}
+
+func (c *conf) WithAtomicSlices() Conf {
+	c.atomicSlices = true
+	return c
+}
+
+// WithKeyNormalizer sets a function to convert the keys into a canonical form
+// The function is applied to the keys on storing the values by the Load, Set, SetDefault and SetProfileDefault
+// functions and on looking them up, so the sources using different styles of the keys converge to one key.
+// It should be set before storing any value, the keys stored before are not converted.
+//
+// Example:
+//
+//	conf.WithKeyNormalizer(conf.DashToDelimiter)
+//	conf.Set("db.max-conns", 10)
+//	conf.GetInt("db.max.conns") == 10
+//
+// The alias to work with an instance of the global configuration manager.
+func WithKeyNormalizer(fn func(key string) string) Conf {
+	return GlobalConf().WithKeyNormalizer(fn)
+}
+
+func (c *conf) WithKeyNormalizer(fn func(key string) string) Conf {
+	c.keyNormalizer = fn
+	return c
+}
+
+// DashToDelimiter is a key normalizer replacing the dashes with the delimiter of the nested keys
+// so the keys like `max-conns` produced by the flags or the environment match the nested `max.conns` keys.
+func DashToDelimiter(key string) string {
+	return strings.ReplaceAll(key, "-", ".")
+}
+
+// normalizeKey converts a given key by the key normalizer, if set
+func (c *conf) normalizeKey(key string) string {
+	if c.keyNormalizer == nil {
+		return key
+	}
+
+	return c.keyNormalizer(key)
+}
+
+// collisions returns the sorted list of the stored keys with a scalar value and the child keys
+func (c *conf) collisions() []string {
+	parents := map[string]struct{}{}
+	scalars := map[string]struct{}{}
+	c.storage().Range(func(key, value interface{}) bool {
+		k := key.(string)
+		for i := range k {
+			if k[i] == '.' {
+				parents[k[:i]] = struct{}{}
+			}
+		}
+		if !isContainer(value) {
+			scalars[k] = struct{}{}
+		}
+		return true
+	})
+
+	var keys []string
+	for key := range scalars {
+		if _, ok := parents[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// WithConflictLogger sets a logger to report the keys that change from a scalar to a container or vice versa
+// during the Load function
+// The alias to work with an instance of the global configuration manager.
+func WithConflictLogger(logger *slog.Logger) Conf {
+	return GlobalConf().WithConflictLogger(logger)
+}
+
+func (c *conf) WithConflictLogger(logger *slog.Logger) Conf {
+	c.conflictLogger = logger
+	return c
+}
+
+// Ready checks the sources of all readers implementing the HealthChecker interface in provided order
+// Returns the first error
+// Can be used in the readiness probes.
+// The alias to work with an instance of the global configuration manager.
+func Ready(ctx context.Context) error {
+	return GlobalConf().Ready(ctx)
+}
+
+func (c *conf) Ready(ctx context.Context) error {
+	for _, reader := range c.readers {
+		if checker, ok := AsHealthChecker(reader); ok {
+			if err := checker.HealthCheck(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *conf) scan(storage *sync.Map, order *keyOrder, source string, data interface{}, key string) {
+	// a typed nil map is stored as nil, so it is not confused with an empty map
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Map && v.IsNil() {
+		data = nil
+	}
+
+	if key != "" {
+		key = c.normalizeKey(key)
+		if c.conflictLogger != nil {
+			if old, ok := storage.Load(key); ok && isContainer(old) != isContainer(data) {
+				c.conflictLogger.Warn("conflicting configuration value",
+					slog.String("key", key),
+					slog.String("old", fmt.Sprintf("%T", old)),
+					slog.String("new", fmt.Sprintf("%T", data)),
+				)
+			}
+		}
+		storage.Store(key, data)
+		order.add(key, source)
+		if slices.ContainsFunc(c.rawKeys, func(k string) bool { return c.normalizeKey(k) == key }) {
+			return
+		}
+		key += "."
+	}
+
+	if m, ok := data.(OrderedMap); ok {
+		for _, kv := range m {
+			c.scan(storage, order, source, kv.Value, key+kv.Key)
+		}
+		return
+	}
+
+	v := reflect.ValueOf(data)
+
+	switch v.Kind() { //nolint:exhaustive // We don't need to check all types
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			c.scan(storage, order, source, iter.Value().Interface(), key+iter.Key().String())
+		}
+	case reflect.Array, reflect.Slice:
+		if c.atomicSlices {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.scan(storage, order, source, v.Index(i).Interface(), key+strconv.Itoa(i))
+		}
+	default:
+	}
+}
+
+func isContainer(data interface{}) bool {
+	switch reflect.ValueOf(data).Kind() { //nolint:exhaustive // We don't need to check all types
+	case reflect.Map, reflect.Array, reflect.Slice:
+		return true
+	default:
+		return false
+	}
+}
+
+// Keys returns the list of the stored keys
+// The alias to work with an instance of the global configuration manager.
+func Keys() []string {
+	return GlobalConf().Keys()
+}
+
+func (c *conf) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var keys []string
+
+	c.storage().Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+
+	c.defaults.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+
+	return keys
+}
+
+// Walk calls a given function for each key and its value in lexical order of the keys
+// The keys and the raw values are collected at once, so the function sees a consistent point-in-time view
+// even if the values are loaded concurrently. The transformers are applied to the values.
+// The iteration stops if the function returns false.
+// The alias to work with an instance of the global configuration manager.
+func Walk(fn func(key string, value interface{}) bool) {
+	GlobalConf().Walk(fn)
+}
+
+func (c *conf) Walk(fn func(key string, value interface{}) bool) {
+	c.mu.RLock()
+	keys := c.keys()
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i], _ = c.lookup(key)
+	}
+	c.mu.RUnlock()
+
+	for i, key := range keys {
+		if !fn(key, c.resolve(key, values[i])) {
+			return
+		}
+	}
+}
+
+// Dump writes all keys with their values and sources in lexical order of the keys to a given writer
+// Each key is written on a separate line in the form of `key = value (source)`, where the source is the same
+// as returned by the GetWithSource function, and the stored values of the keys having a default value
+// are marked as `overrides default`, so it helps to find out why a value is not the expected one.
+// The transformers are applied to the values, so use Redact to hide the secrets.
+//
+// Example:
+//
+//	db.host = db.example.com (reader[1] *conf.env, overrides default)
+//	db.port = 5432 (default)
+//
+// The alias to work with an instance of the global configuration manager.
+func Dump(w io.Writer) {
+	GlobalConf().Dump(w)
+}
+
+func (c *conf) Dump(w io.Writer) {
+	c.mu.RLock()
+	keys := c.keys()
+	c.mu.RUnlock()
+
+	for _, key := range keys {
+		value, source := c.GetWithSource(key)
+		if source != SourceDefault && source != SourceProfileDefault {
+			if _, ok := c.defaults.Load(key); ok {
+				source += ", overrides default"
+			}
+		}
+		fmt.Fprintf(w, "%s = %v (%s)\n", key, value, source)
+	}
+}
+
+// Generation returns the number of the successful calls of the Load function
+// The generation is advanced if all readers succeed, even if the loaded values fail the checks
+// of WithRequiredKeys or WithCollisionCheck, so it can be compared to detect the reloads cheaply.
+// The alias to work with an instance of the global configuration manager.
+func Generation() uint64 {
+	return GlobalConf().Generation()
+}
+
+func (c *conf) Generation() uint64 {
+	return c.generation.Load()
+}
+
+// Checksum returns a stable hash of all keys and their values, including the defaults
+// The keys are hashed in lexical order with the JSON encoded values, so the checksum does not depend
+// on the order of loading and can be compared after reloading to detect the actual changes.
+// The alias to work with an instance of the global configuration manager.
+func Checksum() string {
+	return GlobalConf().Checksum()
+}
+
+func (c *conf) Checksum() string {
+	h := sha256.New()
+	c.Walk(func(key string, value interface{}) bool {
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%#v", value))
+		}
+		fmt.Fprintf(h, "%q=%s\n", key, data)
+		return true
+	})
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// keys returns the sorted list of the unique keys of the storage and the defaults
+func (c *conf) keys() []string {
+	var keys []string
+	c.storage().Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+	c.defaults.Range(func(key, value interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// OrderedKeys returns the list of the stored keys under a given prefix in order of storing
+// The defaults are not included.
+// The order of the keys of the regular maps is random, use OrderedMap in the readers to keep the source order.
+// The alias to work with an instance of the global configuration manager.
+func OrderedKeys(prefix string) []string {
+	return GlobalConf().OrderedKeys(prefix)
+}
+
+func (c *conf) OrderedKeys(prefix string) []string {
+	if prefix != "" {
+		prefix = c.normalizeKey(prefix)
+	}
+
+	return c.order.list(prefix)
+}
+
+// AllSettings returns the nested structure of all values, including the defaults
+// The flattened keys are split by dots and the transformers are applied to the values.
+// The alias to work with an instance of the global configuration manager.
+func AllSettings() map[string]interface{} {
+	return GlobalConf().AllSettings()
+}
+
+func (c *conf) AllSettings() map[string]interface{} {
+	data, _ := c.tree("")
+	if res, ok := data.(map[string]interface{}); ok {
+		return res
+	}
+
+	return map[string]interface{}{}
+}
+
+// GetAll returns a copy of all flattened keys and their values, including the defaults
+// Unlike AllSettings, the keys are not nested, e.g. both `db` and `db.host` are returned,
+// and the transformers are applied to each value. The changes of the returned map do not affect the configuration,
+// but the maps and the slices stored as values are not copied.
+// The alias to work with an instance of the global configuration manager.
+func GetAll() map[string]interface{} {
+	return GlobalConf().GetAll()
+}
+
+func (c *conf) GetAll() map[string]interface{} {
+	res := map[string]interface{}{}
+	c.Walk(func(key string, value interface{}) bool {
+		res[key] = value
+		return true
+	})
+
+	return res
+}
+
+// GetMergedMap deeply merges the nested structures of the values under the given prefixes
+// The maps are merged recursively and the values of the later prefixes take precedence,
+// so `GetMergedMap("defaults", "overrides")` combines a base section with an override one.
+// The prefixes without a map are skipped.
+// The alias to work with an instance of the global configuration manager.
+func GetMergedMap(prefixes ...string) map[string]interface{} {
+	return GlobalConf().GetMergedMap(prefixes...)
+}
+
+func (c *conf) GetMergedMap(prefixes ...string) map[string]interface{} {
+	var res interface{} = map[string]interface{}{}
+	for _, prefix := range prefixes {
+		data, _ := c.tree(prefix)
+		if m, ok := data.(map[string]interface{}); ok {
+			res = merge(res, m)
+		}
+	}
+
+	return res.(map[string]interface{})
+}
+
+// FlatStringMap returns the values of all leaf keys under a given prefix cast to String
+// The keys of the map are the dotted keys relative to the prefix, including the indexes of the slices,
+// so the values of `a.b.0.c` and `a.d` under the prefix `a` are returned as `b.0.c` and `d`.
+// The keys having the child keys are not included. The empty prefix returns all leaf keys.
+// Returns `nil` if there are no keys under the prefix.
+// The alias to work with an instance of the global configuration manager.
+func FlatStringMap(prefix string) map[string]string {
+	return GlobalConf().FlatStringMap(prefix)
+}
+
+func (c *conf) FlatStringMap(prefix string) map[string]string {
+	if prefix != "" {
+		prefix = c.normalizeKey(prefix)
+	}
+
+	c.mu.RLock()
+	keys := c.keys()
+	c.mu.RUnlock()
+
+	parents := map[string]struct{}{}
+	for _, k := range keys {
+		for i := range k {
+			if k[i] == '.' {
+				parents[k[:i]] = struct{}{}
+			}
+		}
+	}
+
+	var res map[string]string
+	for _, k := range keys {
+		if _, ok := parents[k]; ok {
+			continue
+		}
+
+		rel := k
+		if prefix != "" {
+			if !strings.HasPrefix(k, prefix+".") {
+				continue
+			}
+			rel = k[len(prefix)+1:]
+		}
+
+		if res == nil {
+			res = map[string]string{}
+		}
+		res[rel] = cast.ToString(c.Get(k))
+	}
+
+	return res
+}
+
+// GetObject decodes the nested structure of the values under a given key into a given target
+// The target must be a pointer to a struct, a map or a slice, if the key points to a slice.
+// The values are decoded using the `encoding/json` package, so the `json` tags of the struct fields are respected.
+// The alias to work with an instance of the global configuration manager.
+func GetObject(key string, target interface{}) error {
+	return GlobalConf().GetObject(key, target)
+}
+
+func (c *conf) GetObject(key string, target interface{}) error {
+	data, ok := c.tree(key)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	return decodeObject(key, data, target)
+}
+
+// decodeObject decodes a given nested structure of the values under a given key into a given target
+func decodeObject(key string, data, target interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return nil
+}
+
+// UnmarshalMap decodes each named subtree under a given prefix into an item of a given map
+// The target must be a pointer to a map with string keys, the first segments of the keys under the prefix
+// are used as the keys of the map and the items are decoded the same way as by the GetObject function.
+// The existing items of the map are replaced.
+//
+// Example:
+//
+//	conf.Set("plugins.a.path", "/usr/lib/a.so")
+//	conf.Set("plugins.b.path", "/usr/lib/b.so")
+//	var plugins map[string]*Plugin
+//	conf.UnmarshalMap("plugins", &plugins)
+//	plugins["b"].Path == "/usr/lib/b.so"
+//
+// The alias to work with an instance of the global configuration manager.
+func UnmarshalMap(prefix string, target interface{}) error {
+	return GlobalConf().UnmarshalMap(prefix, target)
+}
+
+func (c *conf) UnmarshalMap(prefix string, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Map ||
+		v.Elem().Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%w: target must be a pointer to a map with string keys, got %T", ErrInvalidValue, target)
+	}
+
+	data, ok := c.tree(prefix)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrKeyNotFound, prefix)
+	}
+
+	items, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w %q: expected a map, got %T", ErrInvalidValue, prefix, data)
+	}
+
+	m := v.Elem()
+	if m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(items)))
+	}
+
+	for name, item := range items {
+		value := reflect.New(m.Type().Elem())
+		if err := decodeObject(joinKey(prefix, name), item, value.Interface()); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(name).Convert(m.Type().Key()), value.Elem())
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the result of the AllSettings function
+func (c *conf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.AllSettings())
+}
+
+// SetDefault sets a default value for a key
+// The alias to work with an instance of the global configuration manager.
+func SetDefault(key string, value interface{}) Conf {
+	return GlobalConf().SetDefault(key, value)
+}
+
+func (c *conf) SetDefault(key string, value interface{}) Conf {
+	c.defaults.Store(c.normalizeKey(key), value)
+	return c
+}
+
+// envDefault is a default value read from an environment variable
+type envDefault struct {
+	name     string
+	fallback interface{}
+}
+
+// SetDefaultFromEnv sets a default value for a key to be read from a given environment variable
+// The variable is read on every access to the key, so the changes of the environment are visible,
+// and a given fallback value is used if the variable is not set.
+//
+// Example:
+//
+//	conf.SetDefaultFromEnv("http.port", "PORT", 8080)
+//
+// The alias to work with an instance of the global configuration manager.
+func SetDefaultFromEnv(key, envVar string, fallback interface{}) Conf {
+	return GlobalConf().SetDefaultFromEnv(key, envVar, fallback)
+}
+
+func (c *conf) SetDefaultFromEnv(key, envVar string, fallback interface{}) Conf {
+	c.defaults.Store(c.normalizeKey(key), envDefault{name: envVar, fallback: fallback})
+	return c
+}
+
+// WithAutomaticEnv makes the getters check the environment variable derived from a key before other values
+// The name of the variable is a given prefix followed by the uppercased key with underscores
+// instead of the dots and the dashes, so the key `db.host` is overridden by `DB_HOST`
+// or by `APP_DB_HOST` with the `APP_` prefix. The prefix is used as is, like by NewEnvReader,
+// so it includes the separator and the same prefix can be given to both.
+// The dashes are mapped to the underscores as well, so `max-conns` is read from `MAX_CONNS`,
+// but NewEnvReader loads `MAX_CONNS` as `max.conns`, use WithKeyNormalizer(DashToDelimiter)
+// to access such values by both keys.
+// The variables are read on every access, so the changes of the environment are visible without reloading.
+// The alias to work with an instance of the global configuration manager.
+func WithAutomaticEnv(prefix string) Conf {
+	return GlobalConf().WithAutomaticEnv(prefix)
+}
+
+func (c *conf) WithAutomaticEnv(prefix string) Conf {
+	c.automaticEnv = true
+	c.envPrefix = prefix
+	return c
+}
+
+var envNameReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// envName returns the name of the environment variable for a given key
+func (c *conf) envName(key string) string {
+	return c.envPrefix + strings.ToUpper(envNameReplacer.Replace(key))
+}
+
+// loadDefault returns a default value for a given key and reads the environment variable, if needed
+func (c *conf) loadDefault(key string) (interface{}, bool) {
+	value, ok := c.defaults.Load(key)
+	if e, isEnv := value.(envDefault); isEnv {
+		if v, set := os.LookupEnv(e.name); set {
+			return v, true
+		}
+		return e.fallback, true
+	}
+
+	return value, ok
+}
+
+// WithProfile uses the value of a given key as the name of the active profile
+// The profile defaults take precedence over the generic defaults.
+//
+// Example:
+//
+//	conf.WithProfile("env")
+//	conf.SetProfileDefault("production", "db.host", "db.example.com")
+//	conf.Set("env", "production")
+//	conf.GetString("db.host") == "db.example.com"
+//
+// The alias to work with an instance of the global configuration manager.
+func WithProfile(key string) Conf {
+	return GlobalConf().WithProfile(key)
+}
+
+func (c *conf) WithProfile(key string) Conf {
+	c.profileKey = key
+	return c
+}
+
+// SetProfileDefault sets a default value for a key in a given profile
+// The alias to work with an instance of the global configuration manager.
+func SetProfileDefault(profile, key string, value interface{}) Conf {
+	return GlobalConf().SetProfileDefault(profile, key, value)
+}
+
+func (c *conf) SetProfileDefault(profile, key string, value interface{}) Conf {
+	defaults, _ := c.profiles.LoadOrStore(profile, &sync.Map{})
+	defaults.(*sync.Map).Store(c.normalizeKey(key), value)
+	return c
+}
+
+// Set overrides the current value of a given key.
+// The alias to work with an instance of the global configuration manager.
+func Set(key string, value interface{}) Conf {
+	return GlobalConf().Set(key, value)
+}
+
+func (c *conf) Set(key string, value interface{}) Conf {
+	key = c.normalizeKey(key)
+	c.storage().Store(key, value)
+	c.order.add(key, SourceSet)
+	c.refreshSnapshot()
+	return c
+}
+
+// Get returns a value for a given key if it is set or default value
+// Returns `nil` if key not found
+// The alias to work with an instance of the global configuration manager.
+func Get(key string) interface{} {
+	return GlobalConf().Get(key)
+}
+
+func (c *conf) Get(key string) interface{} {
+	value, _ := c.lookup(key)
+	res, err := c.transformE(key, c.transform(key, value))
+	if err != nil {
+		c.castFailed(key, err)
+	}
+
+	return res
+}
+
+// GetE returns a value for a given key if it is set or default value
+// Returns `nil` and the error of the first failed TransformE, see WithTransformersE.
+// Returns `nil` without error if key not found and the transformers accept it.
+// The alias to work with an instance of the global configuration manager.
+func GetE(key string) (interface{}, error) {
+	return GlobalConf().GetE(key)
+}
+
+func (c *conf) GetE(key string) (interface{}, error) {
+	value, _ := c.lookup(key)
+	res, err := c.transformE(key, c.transform(key, value))
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return res, nil
+}
+
+// resolve applies all transformers to a given value of a given key
+// Returns `nil` if an error-returning transformer fails.
+func (c *conf) resolve(key string, value interface{}) interface{} {
+	res, _ := c.transformE(key, c.transform(key, value))
+	return res
+}
+
+// transformE applies the error-returning transformers to a given value of a given key
+func (c *conf) transformE(key string, value interface{}) (interface{}, error) {
+	for _, tr := range c.transformersE {
+		var err error
+		if value, err = tr(key, value, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// transform applies the transformers to a given value of a given key
+// The chain is halted if a transformer returns a value wrapped by the StopTransform function.
+// The untransformed value is returned if a transformer panics and the recovering is enabled.
+func (c *conf) transform(key string, value interface{}) (res interface{}) {
+	if c.recoverLog != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				c.recoverLog.Error("transformer panicked", slog.String("key", key), slog.Any("panic", r))
+				res = value
+			}
+		}()
+	}
+
+	res = value
+	for _, tr := range c.transformers {
+		res = tr(key, res, c)
+		if s, ok := res.(stopped); ok {
+			return s.value
+		}
+	}
+
+	return res
+}
+
+// The sources of the values returned by the GetWithSource function, except the readers
+const (
+	// SourceSet is the source of the values stored by the Set function
+	SourceSet = "set"
+	// SourceEnv is the source of the values read from the environment variables enabled by WithAutomaticEnv
+	SourceEnv = "env"
+	// SourceProfileDefault is the source of the values stored by the SetProfileDefault function
+	SourceProfileDefault = "profile default"
+	// SourceDefault is the source of the values stored by the SetDefault and SetDefaultFromEnv functions
+	SourceDefault = "default"
+)
+
+// GetWithSource returns a value for a given key and the source of the value
+// The source of the values loaded by the Load function is the reader in the form of `reader[index] name`,
+// where the index is the position of the reader and the name is the same as in LoadError.
+// The sources of the other values are SourceEnv, SourceSet, SourceProfileDefault and SourceDefault.
+// Returns `nil` and an empty source if key not found.
+// The alias to work with an instance of the global configuration manager.
+func GetWithSource(key string) (value interface{}, source string) {
+	return GlobalConf().GetWithSource(key)
+}
+
+func (c *conf) GetWithSource(key string) (value interface{}, source string) {
+	value, source = c.lookupSource(key)
+	if source == SourceSet {
+		if s := c.order.source(c.normalizeKey(key)); s != "" {
+			source = s
+		}
+	}
+
+	return c.resolve(key, value), source
+}
+
+// lookup returns a raw value for a given key from the storage, the profile defaults or the defaults
+func (c *conf) lookup(key string) (interface{}, bool) {
+	value, source := c.lookupSource(key)
+	return value, source != ""
+}
+
+// lookupSource returns a raw value for a given key and its source
+// The source of the stored values is always SourceSet, the actual one is tracked by the order.
+// The source is empty if key not found.
+func (c *conf) lookupSource(key string) (interface{}, string) {
+	key = c.normalizeKey(key)
+	if c.automaticEnv {
+		if value, ok := os.LookupEnv(c.envName(key)); ok {
+			return value, SourceEnv
+		}
+	}
+
+	if value, ok := c.stored(key); ok {
+		return value, SourceSet
+	}
+
+	if defaults := c.profileDefaults(); defaults != nil {
+		if value, ok := defaults.Load(key); ok {
+			return value, SourceProfileDefault
+		}
+	}
+
+	if value, ok := c.loadDefault(key); ok {
+		return value, SourceDefault
+	}
+
+	return nil, ""
+}
+
+// stored returns a raw value for a given key from the snapshot, if enabled, or the storage
+func (c *conf) stored(key string) (interface{}, bool) {
+	if c.readOptimized {
+		if snapshot := c.snapshot.Load(); snapshot != nil {
+			value, ok := (*snapshot)[key]
+			return value, ok
+		}
+	}
+
+	return c.storage().Load(key)
+}
+
+func (c *conf) profileDefaults() *sync.Map {
+	if c.profileKey == "" {
+		return nil
+	}
+
+	profileKey := c.normalizeKey(c.profileKey)
+	profile, ok := c.stored(profileKey)
+	if !ok {
+		profile, ok = c.loadDefault(profileKey)
+	}
+	if !ok {
+		return nil
+	}
+
+	defaults, ok := c.profiles.Load(cast.ToString(profile))
+	if !ok {
+		return nil
+	}
+
+	return defaults.(*sync.Map)
+}
+
+// GetString casts a value for a given key to String
+// The alias to work with an instance of the global configuration manager.
+func GetString(key string) string {
+	return GlobalConf().GetString(key)
+}
+
+func (c *conf) GetString(key string) string {
+	return castE(c, key, cast.ToStringE)
+}
+
+// MaxInterpolationDepth is the maximum nesting level of the references resolved by the GetInterpolated function
+const MaxInterpolationDepth = 10
+
+var refRegexp = regexp.MustCompile(`\$\{([^${}]+)\}`)
+
+// GetInterpolated casts a value for a given key to String and replaces the `${key}` references
+// with the values of the referenced keys
+// The values of the referenced keys are resolved recursively up to MaxInterpolationDepth levels,
+// so the cyclic references are left unresolved at that level instead of looping forever.
+// The references to the keys without a value are left as is.
+//
+// Example:
+//
+//	conf.Set("host", "db.example.com")
+//	conf.Set("dsn", "postgres://${host}:5432")
+//	conf.GetInterpolated("dsn") == "postgres://db.example.com:5432"
+//
+// The alias to work with an instance of the global configuration manager.
+func GetInterpolated(key string) string {
+	return GlobalConf().GetInterpolated(key)
+}
+
+func (c *conf) GetInterpolated(key string) string {
+	return c.interpolate(c.GetString(key), 0)
+}
+
+// interpolate replaces the references in a given string with the values of the referenced keys
+func (c *conf) interpolate(s string, depth int) string {
+	if depth >= MaxInterpolationDepth {
+		return s
+	}
+
+	return refRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		value := c.Get(ref[2 : len(ref)-1])
+		if value == nil {
+			return ref
+		}
+		return c.interpolate(cast.ToString(value), depth+1)
+	})
+}
+
+// GetRune returns the first character of a string value or casts a number as a code point for a given key
+// Returns zero if the string is empty or the value is not a valid character.
+// The alias to work with an instance of the global configuration manager.
+func GetRune(key string) rune {
+	return GlobalConf().GetRune(key)
+}
+
+func (c *conf) GetRune(key string) rune {
+	return castE(c, key, toRuneE)
+}
+
+// GetRuneE returns the first character of a string value or casts a number as a code point for a given key
+// The rest of the string is ignored, so `"abc"` gives `'a'` and `44` gives `','`.
+// Returns an error if the string is empty or the value is not a valid character
+// The alias to work with an instance of the global configuration manager.
+func GetRuneE(key string) (rune, error) {
+	return GlobalConf().GetRuneE(key)
+}
+
+func (c *conf) GetRuneE(key string) (rune, error) {
+	v, err := toRuneE(c.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return v, nil
+}
+
+// GetByte returns the first character of a string value or casts a number for a given key to Byte
+// Returns zero if the string is empty or the value is not a valid character or does not fit Byte.
+// The alias to work with an instance of the global configuration manager.
+func GetByte(key string) byte {
+	return GlobalConf().GetByte(key)
+}
+
+func (c *conf) GetByte(key string) byte {
+	return castE(c, key, toByteE)
+}
+
+// GetByteE returns the first character of a string value or casts a number for a given key to Byte
+// Returns an error if the string is empty or the value is not a valid character or does not fit Byte
+// The alias to work with an instance of the global configuration manager.
+func GetByteE(key string) (byte, error) {
+	return GlobalConf().GetByteE(key)
+}
+
+func (c *conf) GetByteE(key string) (byte, error) {
+	v, err := toByteE(c.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return v, nil
+}
+
+var (
+	errEmptyString = errors.New("empty string")
+	errInvalidRune = errors.New("invalid character")
+)
+
+func toRuneE(value interface{}) (rune, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case rune:
+		if !utf8.ValidRune(v) {
+			return 0, fmt.Errorf("%w: %d", errInvalidRune, v)
+		}
+		return v, nil
+	case string:
+		if v == "" {
+			return 0, errEmptyString
+		}
+		r, size := utf8.DecodeRuneInString(v)
+		if r == utf8.RuneError && size <= 1 {
+			return 0, fmt.Errorf("%w: %q", errInvalidRune, v)
+		}
+		return r, nil
+	}
+
+	n, err := toIntE(value, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	return toRuneE(rune(n)) //nolint:gosec // the range is checked
+}
+
+func toByteE(value interface{}) (byte, error) {
+	r, err := toRuneE(value)
+	if err != nil {
+		return 0, err
+	}
+	if r > math.MaxUint8 {
+		return 0, fmt.Errorf("%w: %q does not fit byte", ErrOverflow, r)
+	}
+
+	return byte(r), nil //nolint:gosec // the range is checked
+}
+
+// GetInt casts a value for a given key to Int
+// The alias to work with an instance of the global configuration manager.
+func GetInt(key string) int {
+	return GlobalConf().GetInt(key)
+}
+
+func (c *conf) GetInt(key string) int {
+	return castE(c, key, cast.ToIntE)
+}
+
+// GetInt8 casts a value for a given key to Int8
+// The alias to work with an instance of the global configuration manager.
+func GetInt8(key string) int8 {
+	return GlobalConf().GetInt8(key)
+}
+
+func (c *conf) GetInt8(key string) int8 {
+	return castE(c, key, cast.ToInt8E)
+}
+
+// GetInt16 casts a value for a given key to Int16
+// The alias to work with an instance of the global configuration manager.
+func GetInt16(key string) int16 {
+	return GlobalConf().GetInt16(key)
+}
+
+func (c *conf) GetInt16(key string) int16 {
+	return castE(c, key, cast.ToInt16E)
+}
+
+// GetInt32 casts a value for a given key to Int32
+// The alias to work with an instance of the global configuration manager.
+func GetInt32(key string) int32 {
+	return GlobalConf().GetInt32(key)
+}
+
+func (c *conf) GetInt32(key string) int32 {
+	return castE(c, key, cast.ToInt32E)
+}
+
+// GetInt64 casts a value for a given key to Int64
+// The alias to work with an instance of the global configuration manager.
+func GetInt64(key string) int64 {
+	return GlobalConf().GetInt64(key)
+}
+
+func (c *conf) GetInt64(key string) int64 {
+	return castE(c, key, cast.ToInt64E)
+}
+
+// GetIntE casts a value for a given key to Int
+// Returns an error if the value cannot be cast to Int or exceeds its range
+// The alias to work with an instance of the global configuration manager.
+func GetIntE(key string) (int, error) {
+	return GlobalConf().GetIntE(key)
+}
+
+func (c *conf) GetIntE(key string) (int, error) {
+	v, err := c.getIntE(key, strconv.IntSize)
+	return int(v), err
+}
+
+// GetInt8E casts a value for a given key to Int8
+// Returns an error if the value cannot be cast to Int8 or exceeds its range
+// The alias to work with an instance of the global configuration manager.
+func GetInt8E(key string) (int8, error) {
+	return GlobalConf().GetInt8E(key)
+}
+
+func (c *conf) GetInt8E(key string) (int8, error) {
+	v, err := c.getIntE(key, 8)
+	return int8(v), err //nolint:gosec // the range is checked
+}
+
+// GetInt16E casts a value for a given key to Int16
+// Returns an error if the value cannot be cast to Int16 or exceeds its range
+// The alias to work with an instance of the global configuration manager.
+func GetInt16E(key string) (int16, error) {
+	return GlobalConf().GetInt16E(key)
+}
+
+func (c *conf) GetInt16E(key string) (int16, error) {
+	v, err := c.getIntE(key, 16)
+	return int16(v), err //nolint:gosec // the range is checked
+}
+
+// GetInt32E casts a value for a given key to Int32
+// Returns an error if the value cannot be cast to Int32 or exceeds its range
+// The alias to work with an instance of the global configuration manager.
+func GetInt32E(key string) (int32, error) {
+	return GlobalConf().GetInt32E(key)
+}
+
+func (c *conf) GetInt32E(key string) (int32, error) {
+	v, err := c.getIntE(key, 32)
+	return int32(v), err //nolint:gosec // the range is checked
+}
+
+// GetInt64E casts a value for a given key to Int64
+// Returns an error if the value cannot be cast to Int64
+// The alias to work with an instance of the global configuration manager.
+func GetInt64E(key string) (int64, error) {
+	return GlobalConf().GetInt64E(key)
+}
+
+func (c *conf) GetInt64E(key string) (int64, error) {
+	return c.getIntE(key, 64)
+}
+
+// getIntE casts a value for a given key to Int64 and checks that it fits the signed integer of the given size
+func (c *conf) getIntE(key string, bitSize int) (int64, error) {
+	v, err := cast.ToInt64E(c.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	if !fitsInt(v, bitSize) {
+		return 0, fmt.Errorf("%w %q: %d does not fit int%d", ErrOverflow, key, v, bitSize)
+	}
+
+	return v, nil
+}
+
+// toIntE casts a given value to Int64 and checks that it fits the signed integer of the given size
+func toIntE(value interface{}, bitSize int) (int64, error) {
+	v, err := cast.ToInt64E(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if !fitsInt(v, bitSize) {
+		return 0, fmt.Errorf("%w: %d does not fit int%d", ErrOverflow, v, bitSize)
+	}
+
+	return v, nil
+}
+
+func fitsInt(v int64, bitSize int) bool {
+	if bitSize >= 64 {
+		return true
+	}
+
+	limit := int64(1) << (bitSize - 1)
+	return v >= -limit && v < limit
+}
+
+// BoolValues is a global extendable list of the string values that should be converted as true or false
+// Both true and false values are explicit, so GetBoolE reports only the strings missing in the list.
+// The strings that are not in the list and cannot be parsed as Bool are converted to false by GetBool.
+//
+// Example:
+//
+//	conf.Set("flag", "sí")
+//	conf.GetBool("flag") == false
+//	conf.BoolValues["sí"] = true
+//	conf.GetBool("flag") == true
+//	conf.Set("flag", "nope")
+//	conf.BoolValues["nope"] = false
+//	conf.GetBool("flag") == false
+var BoolValues = map[string]bool{
+	"yes": true,
+	"Yes": true,
+	"y":   true,
+	"Y":   true,
+	"On":  true,
+	"on":  true,
+	"no":  false,
+	"No":  false,
+	"n":   false,
+	"N":   false,
+	"Off": false,
+	"off": false,
+}
+
+// WithBoolValues sets the string values that should be converted as true or false by this instance
+// The given values are checked before the global BoolValues.
+// The alias to work with an instance of the global configuration manager.
+func WithBoolValues(values map[string]bool) Conf {
+	return GlobalConf().WithBoolValues(values)
+}
+
+func (c *conf) WithBoolValues(values map[string]bool) Conf {
+	c.boolValues = values
+	return c
+}
+
+// GetBool casts a value for a given key to Bool
+// The alias to work with an instance of the global configuration manager.
+func GetBool(key string) bool {
+	return GlobalConf().GetBool(key)
+}
+
+func (c *conf) GetBool(key string) bool {
+	return castE(c, key, c.toBoolE)
+}
+
+// GetBoolE casts a value for a given key to Bool
+// Returns an error if the value is a non-empty string that is not in BoolValues and cannot be parsed as Bool,
+// or the value of any other type cannot be cast to Bool.
+// The alias to work with an instance of the global configuration manager.
+func GetBoolE(key string) (bool, error) {
+	return GlobalConf().GetBoolE(key)
+}
+
+func (c *conf) GetBoolE(key string) (bool, error) {
+	v, err := c.toBoolE(c.Get(key))
+	if err != nil {
+		return false, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return v, nil
+}
+
+// GetStringMapBool casts the values under a given key to a map of Bool
+// The values are converted with the same rules as the GetBool function, including the BoolValues.
+// Returns `nil` if the value is not a map.
+// The alias to work with an instance of the global configuration manager.
+func GetStringMapBool(key string) map[string]bool {
+	return GlobalConf().GetStringMapBool(key)
+}
+
+func (c *conf) GetStringMapBool(key string) map[string]bool {
+	data, _ := c.tree(key)
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string]bool, len(m))
+	for k, v := range m {
+		res[k], _ = c.toBoolE(v)
+	}
+
+	return res
+}
+
+// GetStringMapString casts the values under a given key to a map of String
+// Returns `nil` if the value is not a map.
+// The alias to work with an instance of the global configuration manager.
+func GetStringMapString(key string) map[string]string {
+	return GlobalConf().GetStringMapString(key)
+}
+
+func (c *conf) GetStringMapString(key string) map[string]string {
+	data, _ := c.tree(key)
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string]string, len(m))
+	for k, v := range m {
+		res[k] = cast.ToString(v)
+	}
+
+	return res
+}
+
+func (c *conf) toBoolE(value interface{}) (bool, error) {
+	v, err := cast.ToBoolE(value)
+	if err == nil {
+		return v, nil
+	}
+
+	if s, ok := value.(string); ok {
+		if s == "" {
+			return false, nil
+		}
+		if v, ok := c.boolValues[s]; ok {
+			return v, nil
+		}
+		if v, ok := BoolValues[s]; ok {
+			return v, nil
+		}
+	}
+
+	return false, err
+}
+
+// GetFloat32 casts a value for a given key to Float32
+// The alias to work with an instance of the global configuration manager.
+func GetFloat32(key string) float32 {
+	return GlobalConf().GetFloat32(key)
+}
+
+func (c *conf) GetFloat32(key string) float32 {
+	return castE(c, key, func(value interface{}) (float32, error) {
+		return cast.ToFloat32E(c.normalizeDecimal(value))
+	})
+}
+
+// GetFloat64 casts a value for a given key to Float64
+// The alias to work with an instance of the global configuration manager.
+func GetFloat64(key string) float64 {
+	return GlobalConf().GetFloat64(key)
+}
+
+func (c *conf) GetFloat64(key string) float64 {
+	return castE(c, key, func(value interface{}) (float64, error) {
+		return cast.ToFloat64E(c.normalizeDecimal(value))
+	})
+}
+
+// GetPercent casts a value for a given key to a ratio
+// The strings with the `%` suffix are divided by 100 and all other values are cast as Float64,
+// so `"10%"`, `"0.1"` and `0.1` give `0.1`, and `"100%"` gives `1`.
+// The alias to work with an instance of the global configuration manager.
+func GetPercent(key string) float64 {
+	return GlobalConf().GetPercent(key)
+}
+
+func (c *conf) GetPercent(key string) float64 {
+	return castE(c, key, func(value interface{}) (float64, error) {
+		value = c.normalizeDecimal(value)
+		if s, ok := value.(string); ok {
+			if p, ok := strings.CutSuffix(strings.TrimSpace(s), "%"); ok {
+				v, err := cast.ToFloat64E(strings.TrimSpace(p))
+				return v / 100, err
+			}
+		}
+
+		return cast.ToFloat64E(value)
+	})
+}
+
+// WithDecimalSeparator sets the decimal separator of the string values
+// used by the GetFloat32 and GetFloat64 functions
+// The default separator is `.`.
+// The alias to work with an instance of the global configuration manager.
+func WithDecimalSeparator(sep rune) Conf {
+	return GlobalConf().WithDecimalSeparator(sep)
+}
+
+func (c *conf) WithDecimalSeparator(sep rune) Conf {
+	c.decimalSeparator = sep
+	return c
+}
+
+// normalizeDecimal replaces the configured decimal separator of a string value with `.`
+func (c *conf) normalizeDecimal(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || c.decimalSeparator == 0 || c.decimalSeparator == '.' {
+		return value
+	}
+
+	return strings.ReplaceAll(s, string(c.decimalSeparator), ".")
+}
+
+// GetComplex64 casts a value for a given key to Complex64
+// The strings are parsed in the form of `1+2i`.
+// The alias to work with an instance of the global configuration manager.
+func GetComplex64(key string) complex64 {
+	return GlobalConf().GetComplex64(key)
+}
+
+func (c *conf) GetComplex64(key string) complex64 {
+	return complex64(castE(c, key, toComplex128E))
+}
+
+// GetComplex64E casts a value for a given key to Complex64
+// Returns an error if the value cannot be cast to Complex64
+// The alias to work with an instance of the global configuration manager.
+func GetComplex64E(key string) (complex64, error) {
+	return GlobalConf().GetComplex64E(key)
+}
+
+func (c *conf) GetComplex64E(key string) (complex64, error) {
+	v, err := c.GetComplex128E(key)
+	return complex64(v), err
+}
+
+// GetComplex128 casts a value for a given key to Complex128
+// The strings are parsed in the form of `1+2i`.
+// The alias to work with an instance of the global configuration manager.
+func GetComplex128(key string) complex128 {
+	return GlobalConf().GetComplex128(key)
+}
+
+func (c *conf) GetComplex128(key string) complex128 {
+	return castE(c, key, toComplex128E)
+}
+
+// GetComplex128E casts a value for a given key to Complex128
+// Returns an error if the value cannot be cast to Complex128
+// The alias to work with an instance of the global configuration manager.
+func GetComplex128E(key string) (complex128, error) {
+	return GlobalConf().GetComplex128E(key)
+}
+
+func (c *conf) GetComplex128E(key string) (complex128, error) {
+	v, err := toComplex128E(c.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return v, nil
+}
+
+func toComplex128E(value interface{}) (complex128, error) {
+	switch v := value.(type) {
+	case complex128:
+		return v, nil
+	case complex64:
+		return complex128(v), nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		return strconv.ParseComplex(v, 128)
+	}
+
+	f, err := cast.ToFloat64E(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(f, 0), nil
+}
+
+// GetTime casts a value for a given key to `time.Time`
+// The alias to work with an instance of the global configuration manager.
+func GetTime(key string) time.Time {
+	return GlobalConf().GetTime(key)
+}
+
+func (c *conf) GetTime(key string) time.Time {
+	return castE(c, key, cast.ToTimeE)
+}
+
+// GetTimeUnixMilli casts a value for a given key to `time.Time` treating the numbers as Unix milliseconds
+// The GetTime function treats the numbers as Unix seconds, so the millisecond epoch values are misparsed by it.
+// The time values are returned as is.
+// The alias to work with an instance of the global configuration manager.
+func GetTimeUnixMilli(key string) time.Time {
+	return GlobalConf().GetTimeUnixMilli(key)
+}
+
+func (c *conf) GetTimeUnixMilli(key string) time.Time {
+	return castE(c, key, func(value interface{}) (time.Time, error) {
+		return toUnixTimeE(value, time.UnixMilli)
+	})
+}
+
+// GetTimeUnixNano casts a value for a given key to `time.Time` treating the numbers as Unix nanoseconds
+// The time values are returned as is.
+// The alias to work with an instance of the global configuration manager.
+func GetTimeUnixNano(key string) time.Time {
+	return GlobalConf().GetTimeUnixNano(key)
+}
+
+func (c *conf) GetTimeUnixNano(key string) time.Time {
+	return castE(c, key, func(value interface{}) (time.Time, error) {
+		return toUnixTimeE(value, func(n int64) time.Time { return time.Unix(0, n) })
+	})
+}
+
+// toUnixTimeE casts a given number to `time.Time` using a given function
+// The zero time is returned for nil.
+func toUnixTimeE(value interface{}, fn func(int64) time.Time) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	}
+
+	n, err := cast.ToInt64E(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return fn(n), nil
+}
+
+// GetDuration casts a value for a given key to `time.Duration`
+// The alias to work with an instance of the global configuration manager.
+func GetDuration(key string) time.Duration {
+	return GlobalConf().GetDuration(key)
+}
+
+func (c *conf) GetDuration(key string) time.Duration {
+	return castE(c, key, c.toDurationE)
+}
+
+// WithDurationUnit sets the unit of the numbers and the numeric strings used by the GetDuration function
+// By default the numbers and the numeric strings, e.g. `"30"`, are converted as nanoseconds.
+// The values of `time.Duration` type and the strings with a unit, e.g. `"1m"`, are not affected.
+//
+// Example:
+//
+//	conf.WithDurationUnit(time.Second)
+//	conf.Set("timeout", "30")
+//	conf.GetDuration("timeout") == 30 * time.Second
+//
+// The alias to work with an instance of the global configuration manager.
+func WithDurationUnit(unit time.Duration) Conf {
+	return GlobalConf().WithDurationUnit(unit)
+}
+
+func (c *conf) WithDurationUnit(unit time.Duration) Conf {
+	c.durationUnit = unit
+	return c
+}
+
+// toDurationE casts a given value to `time.Duration` using the configured unit for the numeric values
+func (c *conf) toDurationE(value interface{}) (time.Duration, error) {
+	if c.durationUnit == 0 {
+		return cast.ToDurationE(value)
+	}
+
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return c.parseDuration(v)
+	case json.Number:
+		return c.parseDuration(v.String())
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() { //nolint:exhaustive // the rest of the kinds are cast as is
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(v.Int()) * c.durationUnit, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(v.Uint()) * c.durationUnit, nil //nolint:gosec // the durations are limited by int64 anyway
+	case reflect.Float32, reflect.Float64:
+		return time.Duration(v.Float() * float64(c.durationUnit)), nil
+	}
+
+	return cast.ToDurationE(value)
+}
+
+// parseDuration converts a numeric string using the configured unit or parses it as a duration string
+func (c *conf) parseDuration(s string) (time.Duration, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(i) * c.durationUnit, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return time.Duration(f * float64(c.durationUnit)), nil
+	}
+
+	return cast.ToDurationE(s)
+}
+
+// GetStringD casts a value for a given key to String
+// Returns a given default value if the key is not found or the value cannot be cast
+// Unlike the SetDefault function, the default value is also returned if the stored value cannot be cast,
+// e.g. a map for GetStringD or a non-numeric string for GetIntD.
+// The alias to work with an instance of the global configuration manager.
+func GetStringD(key, def string) string {
+	return GlobalConf().GetStringD(key, def)
+}
+
+func (c *conf) GetStringD(key, def string) string {
+	return castD(c, key, def, cast.ToStringE)
+}
+
+// GetIntD casts a value for a given key to Int
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetIntD(key string, def int) int {
+	return GlobalConf().GetIntD(key, def)
+}
+
+func (c *conf) GetIntD(key string, def int) int {
+	return castD(c, key, def, func(value interface{}) (int, error) {
+		v, err := toIntE(value, strconv.IntSize)
+		return int(v), err
+	})
+}
+
+// GetInt8D casts a value for a given key to Int8
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetInt8D(key string, def int8) int8 {
+	return GlobalConf().GetInt8D(key, def)
+}
+
+func (c *conf) GetInt8D(key string, def int8) int8 {
+	return castD(c, key, def, func(value interface{}) (int8, error) {
+		v, err := toIntE(value, 8)
+		return int8(v), err //nolint:gosec // the range is checked
+	})
+}
+
+// GetInt16D casts a value for a given key to Int16
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetInt16D(key string, def int16) int16 {
+	return GlobalConf().GetInt16D(key, def)
+}
+
+func (c *conf) GetInt16D(key string, def int16) int16 {
+	return castD(c, key, def, func(value interface{}) (int16, error) {
+		v, err := toIntE(value, 16)
+		return int16(v), err //nolint:gosec // the range is checked
+	})
+}
+
+// GetInt32D casts a value for a given key to Int32
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetInt32D(key string, def int32) int32 {
+	return GlobalConf().GetInt32D(key, def)
+}
+
+func (c *conf) GetInt32D(key string, def int32) int32 {
+	return castD(c, key, def, func(value interface{}) (int32, error) {
+		v, err := toIntE(value, 32)
+		return int32(v), err //nolint:gosec // the range is checked
+	})
+}
+
+// GetInt64D casts a value for a given key to Int64
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetInt64D(key string, def int64) int64 {
+	return GlobalConf().GetInt64D(key, def)
+}
+
+func (c *conf) GetInt64D(key string, def int64) int64 {
+	return castD(c, key, def, func(value interface{}) (int64, error) {
+		return toIntE(value, 64)
+	})
+}
+
+// GetBoolD casts a value for a given key to Bool
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetBoolD(key string, def bool) bool {
+	return GlobalConf().GetBoolD(key, def)
+}
+
+func (c *conf) GetBoolD(key string, def bool) bool {
+	return castD(c, key, def, c.toBoolE)
+}
+
+// GetFloat32D casts a value for a given key to Float32
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetFloat32D(key string, def float32) float32 {
+	return GlobalConf().GetFloat32D(key, def)
+}
+
+func (c *conf) GetFloat32D(key string, def float32) float32 {
+	return castD(c, key, def, func(value interface{}) (float32, error) {
+		return cast.ToFloat32E(c.normalizeDecimal(value))
+	})
+}
+
+// GetFloat64D casts a value for a given key to Float64
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetFloat64D(key string, def float64) float64 {
+	return GlobalConf().GetFloat64D(key, def)
+}
+
+func (c *conf) GetFloat64D(key string, def float64) float64 {
+	return castD(c, key, def, func(value interface{}) (float64, error) {
+		return cast.ToFloat64E(c.normalizeDecimal(value))
+	})
+}
+
+// GetComplex64D casts a value for a given key to Complex64
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetComplex64D(key string, def complex64) complex64 {
+	return GlobalConf().GetComplex64D(key, def)
+}
+
+func (c *conf) GetComplex64D(key string, def complex64) complex64 {
+	return castD(c, key, def, func(value interface{}) (complex64, error) {
+		v, err := toComplex128E(value)
+		return complex64(v), err
+	})
+}
+
+// GetComplex128D casts a value for a given key to Complex128
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetComplex128D(key string, def complex128) complex128 {
+	return GlobalConf().GetComplex128D(key, def)
+}
+
+func (c *conf) GetComplex128D(key string, def complex128) complex128 {
+	return castD(c, key, def, toComplex128E)
+}
+
+// GetTimeD casts a value for a given key to `time.Time`
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetTimeD(key string, def time.Time) time.Time {
+	return GlobalConf().GetTimeD(key, def)
+}
+
+func (c *conf) GetTimeD(key string, def time.Time) time.Time {
+	return castD(c, key, def, cast.ToTimeE)
+}
+
+// GetDurationD casts a value for a given key to `time.Duration`
+// Returns a given default value if the key is not found or the value cannot be cast
+// The alias to work with an instance of the global configuration manager.
+func GetDurationD(key string, def time.Duration) time.Duration {
+	return GlobalConf().GetDurationD(key, def)
+}
+
+func (c *conf) GetDurationD(key string, def time.Duration) time.Duration {
+	return castD(c, key, def, c.toDurationE)
+}
+
+// GetStringMapStringD casts the values under a given key to a map of String merged over a given default map
+// The values of the configuration take precedence, so the default map provides the values of the missing keys,
+// e.g. the baseline labels of a service. The given default map is not modified.
+// The alias to work with an instance of the global configuration manager.
+func GetStringMapStringD(key string, def map[string]string) map[string]string {
+	return GlobalConf().GetStringMapStringD(key, def)
+}
+
+func (c *conf) GetStringMapStringD(key string, def map[string]string) map[string]string {
+	res := maps.Clone(def)
+	values := c.GetStringMapString(key)
+	if res == nil {
+		return values
+	}
+
+	maps.Copy(res, values)
+	return res
+}
+
+// GetStringPtr casts a value for a given key to String and returns a pointer to it
+// Returns `nil` if the key is not found or the value cannot be cast,
+// so an unset key can be distinguished from an empty string, e.g. for the optional fields of a struct.
+// The alias to work with an instance of the global configuration manager.
+func GetStringPtr(key string) *string {
+	return GlobalConf().GetStringPtr(key)
+}
+
+func (c *conf) GetStringPtr(key string) *string {
+	return castPtr(c, key, cast.ToStringE)
+}
+
+// GetIntPtr casts a value for a given key to Int and returns a pointer to it
+// Returns `nil` if the key is not found or the value cannot be cast,
+// so an unset key can be distinguished from zero, e.g. for the optional fields of a struct.
+// The alias to work with an instance of the global configuration manager.
+func GetIntPtr(key string) *int {
+	return GlobalConf().GetIntPtr(key)
+}
+
+func (c *conf) GetIntPtr(key string) *int {
+	return castPtr(c, key, func(value interface{}) (int, error) {
+		v, err := toIntE(value, strconv.IntSize)
+		return int(v), err
+	})
+}
+
+// GetBoolPtr casts a value for a given key to Bool and returns a pointer to it
+// Returns `nil` if the key is not found or the value cannot be cast,
+// so an unset key can be distinguished from false, e.g. for the optional fields of a struct.
+// The alias to work with an instance of the global configuration manager.
+func GetBoolPtr(key string) *bool {
+	return GlobalConf().GetBoolPtr(key)
+}
+
+func (c *conf) GetBoolPtr(key string) *bool {
+	return castPtr(c, key, c.toBoolE)
+}
+
+// GetByteSize parses a value for a given key as a size, e.g. `1.5GB`, and returns the number of bytes and the unit
+// The decimal units (KB, MB, GB, TB, PB) and the binary units (KiB, MiB, GiB, TiB, PiB) are matched case-insensitively.
+// The numbers and the strings without a unit are the bytes, so the unit is `B`.
+// Returns an error if the key is not found, the value cannot be parsed, is negative or does not fit uint64.
+// The alias to work with an instance of the global configuration manager.
+func GetByteSize(key string) (value uint64, unit string, err error) {
+	return GlobalConf().GetByteSize(key)
+}
+
+func (c *conf) GetByteSize(key string) (value uint64, unit string, err error) {
+	raw := c.Get(key)
+	if raw == nil {
+		return 0, "", fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	value, unit, err = parseByteSize(raw)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return value, unit, nil
+}
+
+// GetNumberWithUnit parses a value for a given key as a number with a unit, e.g. `5m`,
+// and returns the number multiplied by the multiplier of the unit from a given table
+// The unit is the text after the number, the white spaces between them are ignored, and it is matched
+// case-sensitively, so any units can be supported, e.g. the minutes `m` and the megabytes `M`.
+// The numbers and the strings without a unit use the multiplier of the empty unit, if present, or 1.
+//
 // Example:
 //
-//	conf.GetSet("flag", "sí")
-//	conf.GetBool("flag") == false
-//	conf.TrueValues["sí"] = true
-//	conf.GetBool("flag") == true
-var BoolValues = map[string]bool{
-	"yes": true,
-	"Yes": true,
-	"y":   true,
-	"Y":   true,
-	"On":  true,
-	"on":  true,
+//	units := map[string]float64{"s": 1, "m": 60, "h": 3600}
+//	conf.Set("timeout", "1.5m")
+//	conf.GetNumberWithUnit("timeout", units) == 90
+//
+// Returns an error if the key is not found, the value cannot be parsed or the unit is unknown.
+// The alias to work with an instance of the global configuration manager.
+func GetNumberWithUnit(key string, units map[string]float64) (float64, error) {
+	return GlobalConf().GetNumberWithUnit(key, units)
 }
 
-// GetBool casts a value for a given key to Bool
+func (c *conf) GetNumberWithUnit(key string, units map[string]float64) (float64, error) {
+	raw := c.Get(key)
+	if raw == nil {
+		return 0, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	value, err := parseNumberWithUnit(raw, units)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return value, nil
+}
+
+// GetStringSlice casts a value for a given key to a slice of strings
+// The strings are split by the white spaces, so `"a b c"` gives `["a", "b", "c"]`.
+// If the list separator is set by WithListSeparator, the strings containing it are split by it
+// and the items are trimmed, so `"a,b,c"` gives `["a", "b", "c"]` with the `,` separator.
+// If any of the items starts with a double quote, the string is parsed as a single CSV record,
+// so the quoted items can contain the list separator or the white spaces,
+// e.g. `"a,b",c` gives `["a,b", "c"]` and `"a b" c` gives `["a b", "c"]`.
 // The alias to work with an instance of the global configuration manager.
-func GetBool(key string) bool {
-	return globalConf.GetBool(key)
+func GetStringSlice(key string) []string {
+	return GlobalConf().GetStringSlice(key)
 }
 
-func (c *conf) GetBool(key string) bool {
-	value := c.Get(key)
+func (c *conf) GetStringSlice(key string) []string {
+	return castE(c, key, c.toStringSliceE)
+}
 
-	if v, err := cast.ToBoolE(value); err == nil {
-		return v
+// WithListSeparator sets the separator of the string values used by the GetStringSlice function
+// The separator is not set by default, so the strings are split by the white spaces only.
+// The strings without the separator are still split by the white spaces.
+// The alias to work with an instance of the global configuration manager.
+func WithListSeparator(sep rune) Conf {
+	return GlobalConf().WithListSeparator(sep)
+}
+
+func (c *conf) WithListSeparator(sep rune) Conf {
+	c.listSeparator = sep
+	return c
+}
+
+// toStringSliceE casts a given value to a slice of strings splitting the strings by the list separator, if set,
+// or by the white spaces
+// The strings with the quoted items are read by the CSV reader to respect the quoted separators.
+func (c *conf) toStringSliceE(value interface{}) ([]string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return cast.ToStringSliceE(value)
+	}
+
+	sep := c.listSeparator
+	var items []string
+	if sep == 0 || !strings.ContainsRune(s, sep) {
+		sep = ' '
+		items = strings.Fields(s)
+	} else {
+		items = strings.Split(s, string(sep))
+	}
+
+	// the quoted items may contain the separator, so the string is parsed as a CSV record
+	if slices.ContainsFunc(items, func(item string) bool { return strings.HasPrefix(strings.TrimSpace(item), `"`) }) {
+		if sep == ' ' {
+			// the CSV reader splits by the spaces only, the empty items of the repeated spaces are skipped below
+			s = strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) {
+					return ' '
+				}
+				return r
+			}, s)
+		}
+		r := csv.NewReader(strings.NewReader(s))
+		r.Comma = sep
+		r.TrimLeadingSpace = sep != ' '
+		record, err := r.Read()
+		if err != nil {
+			return nil, err
+		}
+		items = record
 	}
 
-	if v, ok := value.(string); ok {
-		return BoolValues[v]
+	var res []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
 	}
 
-	return false
+	return res, nil
 }
 
-// GetFloat32 casts a value for a given key to Float32
+// GetStringSliceUnique casts a value for a given key to a slice of strings without duplicates
+// The first occurrence of each string is kept, so the order of the merged lists is preserved,
+// e.g. `["a", "b", "a", "c"]` gives `["a", "b", "c"]`.
 // The alias to work with an instance of the global configuration manager.
-func GetFloat32(key string) float32 {
-	return globalConf.GetFloat32(key)
+func GetStringSliceUnique(key string) []string {
+	return GlobalConf().GetStringSliceUnique(key)
 }
 
-func (c *conf) GetFloat32(key string) float32 {
-	return cast.ToFloat32(c.Get(key))
+func (c *conf) GetStringSliceUnique(key string) []string {
+	values := c.GetStringSlice(key)
+	if values == nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(values))
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		res = append(res, v)
+	}
+
+	return res
 }
 
-// GetFloat64 casts a value for a given key to Float64
+// GetIntSlice casts a value for a given key to a slice of Int casting each item separately
+// The items of different types are supported, e.g. `[]interface{}{1, "2"}` gives `[]int{1, 2}`,
+// and the strings are split the same way as by the GetStringSlice function.
+// Returns `nil` if any of the items cannot be cast to Int.
 // The alias to work with an instance of the global configuration manager.
-func GetFloat64(key string) float64 {
-	return globalConf.GetFloat64(key)
+func GetIntSlice(key string) []int {
+	return GlobalConf().GetIntSlice(key)
 }
 
-func (c *conf) GetFloat64(key string) float64 {
-	return cast.ToFloat64(c.Get(key))
+func (c *conf) GetIntSlice(key string) []int {
+	return castE(c, key, func(value interface{}) ([]int, error) {
+		if value == nil {
+			return nil, nil
+		}
+		if s, ok := value.(string); ok {
+			items, err := c.toStringSliceE(s)
+			if err != nil {
+				return nil, err
+			}
+			value = items
+		}
+
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return cast.ToIntSliceE(value)
+		}
+
+		res := make([]int, v.Len())
+		for i := range res {
+			item, err := toIntE(v.Index(i).Interface(), strconv.IntSize)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			res[i] = int(item)
+		}
+
+		return res, nil
+	})
 }
 
-// GetTime casts a value for a given key to `time.Time`
+// GetLines splits a string value for a given key into the trimmed lines without the blank ones
+// It is useful for the values mounted from the files, e.g. a list of the allowed IPs one per line.
+// The values of other types are cast to a slice of strings.
 // The alias to work with an instance of the global configuration manager.
-func GetTime(key string) time.Time {
-	return globalConf.GetTime(key)
+func GetLines(key string) []string {
+	return GlobalConf().GetLines(key)
 }
 
-func (c *conf) GetTime(key string) time.Time {
-	return cast.ToTime(c.Get(key))
+func (c *conf) GetLines(key string) []string {
+	return castE(c, key, func(value interface{}) ([]string, error) {
+		s, ok := value.(string)
+		if !ok {
+			return cast.ToStringSliceE(value)
+		}
+
+		var res []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				res = append(res, line)
+			}
+		}
+
+		return res, nil
+	})
 }
 
-// GetDuration casts a value for a given key to `time.Duration`
+// GetFileContent reads the file by the path stored as a value for a given key
+// It is useful for the secrets mounted as files, e.g. `db.password_file` of the Docker secrets convention.
+// Returns an error if the key is not found or the file cannot be read, e.g. `os.ErrNotExist`.
 // The alias to work with an instance of the global configuration manager.
-func GetDuration(key string) time.Duration {
-	return globalConf.GetDuration(key)
+func GetFileContent(key string) ([]byte, error) {
+	return GlobalConf().GetFileContent(key)
 }
 
-func (c *conf) GetDuration(key string) time.Duration {
-	return cast.ToDuration(c.Get(key))
+func (c *conf) GetFileContent(key string) ([]byte, error) {
+	value := c.Get(key)
+	if value == nil {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	path, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return readFile(key, path)
+}
+
+// GetCertificate loads a certificate and a private key from the values for the given keys
+// Each value is either the inline PEM data, if it contains the `-----BEGIN` marker, or a path to the PEM file.
+// The alias to work with an instance of the global configuration manager.
+func GetCertificate(certKey, keyKey string) (tls.Certificate, error) {
+	return GlobalConf().GetCertificate(certKey, keyKey)
+}
+
+func (c *conf) GetCertificate(certKey, keyKey string) (tls.Certificate, error) {
+	cert, err := c.getPEM(certKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	key, err := c.getPEM(keyKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	res, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("%w %q: %w", ErrInvalidValue, certKey, err)
+	}
+
+	return res, nil
+}
+
+// GetCertPool loads the certificates from the values for a given key into a pool
+// The value is either a string or a list of strings, each of them is either the inline PEM data,
+// if it contains the `-----BEGIN` marker, or a path to the PEM file.
+// The pool does not include the system certificates.
+// The alias to work with an instance of the global configuration manager.
+func GetCertPool(key string) (*x509.CertPool, error) {
+	return GlobalConf().GetCertPool(key)
+}
+
+func (c *conf) GetCertPool(key string) (*x509.CertPool, error) {
+	value := c.Get(key)
+	if value == nil {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	var items []string
+	if s, ok := value.(string); ok {
+		items = []string{s}
+	} else {
+		var err error
+		if items, err = cast.ToStringSliceE(value); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+		}
+	}
+
+	pool := x509.NewCertPool()
+	for _, item := range items {
+		data, err := readPEM(key, item)
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, ErrNoCertificates)
+		}
+	}
+
+	return pool, nil
+}
+
+// getPEM returns the PEM data of a value for a given key
+func (c *conf) getPEM(key string) ([]byte, error) {
+	value := c.Get(key)
+	if value == nil {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	s, err := cast.ToStringE(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return readPEM(key, s)
+}
+
+// readPEM returns a given inline PEM data or reads the PEM file by a given path stored by a given key
+func readPEM(key, s string) ([]byte, error) {
+	if strings.Contains(s, "-----BEGIN") {
+		return []byte(s), nil
+	}
+
+	return readFile(key, s)
+}
+
+// readFile reads the file by a given path stored by a given key
+func readFile(key, path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // the path is a part of the configuration
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	}
+
+	return data, nil
+}
+
+// GetMapSlice casts a value for a given key to a slice of maps
+// Returns `nil` if the value is not a slice or any of its items is not a map
+// The alias to work with an instance of the global configuration manager.
+func GetMapSlice(key string) []map[string]interface{} {
+	return GlobalConf().GetMapSlice(key)
+}
+
+func (c *conf) GetMapSlice(key string) []map[string]interface{} {
+	value := c.Get(key)
+	if v, ok := value.([]map[string]interface{}); ok {
+		return v
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+
+	res := make([]map[string]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		m, err := cast.ToStringMapE(v.Index(i).Interface())
+		if err != nil {
+			return nil
+		}
+		res = append(res, m)
+	}
+
+	return res
+}
+
+// WithStrictCast enables reporting of the cast failures of the getters without error, e.g. GetInt
+// The failures are logged by the given logger or cause a panic if the logger is nil.
+// The missing keys are not reported, but the errors of the transformers set by WithTransformersE are,
+// e.g. the empty values rejected by RequireNonEmpty.
+// By default the getters return the zero values silently.
+// The alias to work with an instance of the global configuration manager.
+func WithStrictCast(logger *slog.Logger) Conf {
+	return GlobalConf().WithStrictCast(logger)
+}
+
+func (c *conf) WithStrictCast(logger *slog.Logger) Conf {
+	c.strictCast = true
+	c.strictLogger = logger
+	return c
+}
+
+// castE casts a value for a given key using a given function and reports the failure in the strict mode
+func castE[T any](c *conf, key string, fn func(interface{}) (T, error)) T {
+	value := c.Get(key)
+	v, err := fn(value)
+	if err != nil && value != nil {
+		c.castFailed(key, err)
+	}
+
+	return v
+}
+
+// castFailed reports a given cast failure of a given key if the strict cast is enabled
+func (c *conf) castFailed(key string, err error) {
+	if !c.strictCast {
+		return
+	}
+
+	err = fmt.Errorf("%w %q: %w", ErrInvalidValue, key, err)
+	if c.strictLogger == nil {
+		panic(err)
+	}
+	c.strictLogger.Error("failed to cast configuration value", slog.String("key", key), slog.Any("error", err))
+}
+
+// castPtr casts a value for a given key using a given function and returns a pointer to the result
+// Returns `nil` if the key is not found or the value cannot be cast, the failures are reported like by castE
+func castPtr[T any](c *conf, key string, fn func(interface{}) (T, error)) *T {
+	value := c.Get(key)
+	if value == nil {
+		return nil
+	}
+
+	v, err := fn(value)
+	if err != nil {
+		c.castFailed(key, err)
+		return nil
+	}
+
+	return &v
+}
+
+// castD casts a value for a given key using a given function
+// and returns a given default value if the key is not found or the value cannot be cast
+func castD[T any](c *conf, key string, def T, fn func(interface{}) (T, error)) T {
+	value := c.Get(key)
+	if value == nil {
+		return def
+	}
+
+	v, err := fn(value)
+	if err != nil {
+		return def
+	}
+
+	return v
 }
@@ -1,12 +1,31 @@
 package conf_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sv-tools/conf"
@@ -65,7 +84,7 @@ func TestConf(t *testing.T) {
 
 		require.EqualError(t,
 			conf.New().WithReaders(newReader(t, "", nil, errFake)).Load(context.Background()),
-			"fake error",
+			"reader *conf_test.testReader: fake error",
 		)
 	})
 
@@ -84,6 +103,7 @@ func TestConf(t *testing.T) {
 		require.Equal(t, data4, c.Get("data4"))
 		require.Equal(t, 1, c.Get("data4.0"))
 		require.Equal(t, "2", c.Get("data4.1"))
+		require.Equal(t, []int{1, 2}, c.GetIntSlice("data4"))
 
 		require.Equal(t, data3, c.Get("data3"))
 		require.Nil(t, c.Get("data3.no"))
@@ -153,6 +173,28 @@ func TestConf(t *testing.T) {
 	})
 }
 
+func TestConf_Profile(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithProfile("env")
+	c.SetDefault("db.host", "localhost")
+	c.SetProfileDefault("staging", "db.host", "staging.example.com")
+	c.SetProfileDefault("production", "db.host", "production.example.com")
+	require.Equal(t, "localhost", c.Get("db.host"))
+
+	c.SetDefault("env", "staging")
+	require.Equal(t, "staging.example.com", c.Get("db.host"))
+
+	c.Set("env", "production")
+	require.Equal(t, "production.example.com", c.Get("db.host"))
+
+	c.Set("env", "development")
+	require.Equal(t, "localhost", c.Get("db.host"))
+
+	c.Set("db.host", "db.example.com")
+	require.Equal(t, "db.example.com", c.Get("db.host"))
+}
+
 func TestConf_GetBool(t *testing.T) {
 	t.Parallel()
 
@@ -183,7 +225,7 @@ func TestConf_GetBool(t *testing.T) {
 		require.Equal(t, expectedValue, c.GetBool("flag"), "%T: %v", rawValue, rawValue)
 	}
 
-	conf.BoolValues["sí"] = true
+	c.WithBoolValues(map[string]bool{"sí": true})
 	c.Set("flag", "sí")
 	require.True(t, c.GetBool("flag"))
 }
@@ -240,6 +282,21 @@ func TestConf_GetInt(t *testing.T) {
 	}
 }
 
+func TestConf_GetInt_JSONNumber(t *testing.T) {
+	t.Parallel()
+
+	var data map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(`{"id": 9007199254740993}`))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&data))
+
+	c := conf.New().WithReaders(newReader(t, "", data, nil))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, json.Number("9007199254740993"), c.Get("id"))
+	require.Equal(t, 9007199254740993, c.GetInt("id"))
+	require.Equal(t, int64(9007199254740993), c.GetInt64("id"))
+}
+
 func TestConf_GetFloat(t *testing.T) {
 	t.Parallel()
 
@@ -294,6 +351,27 @@ func TestConf_GetTime(t *testing.T) {
 	}
 }
 
+func TestConf_GetTimeUnix(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	c := conf.New()
+	c.Set("millis", t1.UnixMilli())
+	c.Set("string", strconv.FormatInt(t1.UnixMilli(), 10))
+	c.Set("nanos", t1.UnixNano())
+	c.Set("time", t1)
+	c.Set("invalid", "yesterday")
+
+	require.True(t, t1.Equal(c.GetTimeUnixMilli("millis")))
+	require.True(t, t1.Equal(c.GetTimeUnixMilli("string")))
+	require.True(t, t1.Equal(c.GetTimeUnixNano("nanos")))
+	require.True(t, t1.Equal(c.GetTimeUnixMilli("time")))
+	require.True(t, t1.Equal(c.GetTimeUnixNano("time")))
+	require.NotEqual(t, 2024, c.GetTime("millis").Year())
+	require.True(t, c.GetTimeUnixMilli("invalid").IsZero())
+	require.True(t, c.GetTimeUnixNano("no key").IsZero())
+}
+
 func TestConf_GetDuration(t *testing.T) {
 	t.Parallel()
 
@@ -393,3 +471,2013 @@ func TestGlobalConf_WithTransformer(t *testing.T) {
 	require.Equal(t, "33", c.Get("foo"))
 	require.Equal(t, 101, c.Get("bar"))
 }
+
+func TestConf_GetMapSlice(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []map[string]interface{}{
+				{
+					"c": 1,
+				},
+				{
+					"d": 2,
+				},
+			},
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"host": "foo"},
+			map[interface{}]interface{}{"host": "bar"},
+		},
+		"mixed": []interface{}{
+			map[string]interface{}{"host": "foo"},
+			42,
+		},
+		"xyz": []int{1, 2, 3},
+		"foo": "bar",
+	}, nil))
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, []map[string]interface{}{{"c": 1}, {"d": 2}}, c.GetMapSlice("a.b"))
+	require.Equal(t, []map[string]interface{}{{"host": "foo"}, {"host": "bar"}}, c.GetMapSlice("servers"))
+	require.Nil(t, c.GetMapSlice("mixed"))
+	require.Nil(t, c.GetMapSlice("xyz"))
+	require.Nil(t, c.GetMapSlice("foo"))
+	require.Nil(t, c.GetMapSlice("no key"))
+}
+
+func TestConf_InsertTransformer(t *testing.T) {
+	t.Parallel()
+
+	appendTransform := func(suffix string) conf.Transform {
+		return func(_ string, value interface{}, _ conf.Conf) interface{} {
+			return cast.ToString(value) + suffix
+		}
+	}
+
+	c := conf.New().WithTransformers(appendTransform("-a"), appendTransform("-b"))
+	c.Set("foo", "foo")
+	require.Len(t, c.Transformers(), 2)
+	require.Equal(t, "foo-a-b", c.Get("foo"))
+
+	c.InsertTransformer(0, appendTransform("-first"))
+	require.Len(t, c.Transformers(), 3)
+	require.Equal(t, "foo-first-a-b", c.Get("foo"))
+
+	c.InsertTransformer(2, appendTransform("-middle"))
+	require.Equal(t, "foo-first-a-middle-b", c.Get("foo"))
+
+	c.InsertTransformer(100, appendTransform("-last"))
+	require.Equal(t, "foo-first-a-middle-b-last", c.Get("foo"))
+
+	c.InsertTransformer(-1, appendTransform("-negative"))
+	require.Equal(t, "foo-negative-first-a-middle-b-last", c.Get("foo"))
+
+	transformers := c.Transformers()
+	transformers[0] = appendTransform("-changed")
+	require.Equal(t, "foo-negative-first-a-middle-b-last", c.Get("foo"))
+}
+
+func TestConf_WithConflictLogger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scalar to map", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		c := conf.New().WithConflictLogger(slog.New(slog.NewTextHandler(&buf, nil))).WithReaders(
+			newReader(t, "", map[string]interface{}{"a": 5}, nil),
+			newReader(t, "", map[string]interface{}{"a": map[string]interface{}{"b": 6}}, nil),
+		)
+		require.NoError(t, c.Load(context.Background()))
+		require.Equal(t, map[string]interface{}{"b": 6}, c.Get("a"))
+		require.Equal(t, 6, c.Get("a.b"))
+		require.Contains(t, buf.String(), `key=a old=int new="map[string]interface {}"`)
+	})
+
+	t.Run("map to scalar", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		c := conf.New().WithConflictLogger(slog.New(slog.NewTextHandler(&buf, nil))).WithReaders(
+			newReader(t, "", map[string]interface{}{"a": map[string]interface{}{"b": 6}}, nil),
+			newReader(t, "", map[string]interface{}{"a": 5}, nil),
+		)
+		require.NoError(t, c.Load(context.Background()))
+		require.Equal(t, 5, c.Get("a"))
+		require.Equal(t, 6, c.Get("a.b"))
+		require.Contains(t, buf.String(), `key=a old="map[string]interface {}" new=int`)
+	})
+
+	t.Run("no conflict", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		c := conf.New().WithConflictLogger(slog.New(slog.NewTextHandler(&buf, nil))).WithReaders(
+			newReader(t, "", map[string]interface{}{"a": 5}, nil),
+			newReader(t, "", map[string]interface{}{"a": 6}, nil),
+		)
+		require.NoError(t, c.Load(context.Background()))
+		require.Equal(t, 6, c.Get("a"))
+		require.Empty(t, buf.String())
+	})
+}
+
+func TestConf_GetBoolE(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	data := map[interface{}]bool{
+		"0":     false,
+		"1":     true,
+		0:       false,
+		2:       true,
+		true:    true,
+		false:   false,
+		"":      false,
+		"True":  true,
+		"False": false,
+		"yes":   true,
+		"no":    false,
+		"No":    false,
+		nil:     false,
+	}
+	for rawValue, expectedValue := range data {
+		c.Set("flag", rawValue)
+
+		v, err := c.GetBoolE("flag")
+		require.NoError(t, err, "%T: %v", rawValue, rawValue)
+		require.Equal(t, expectedValue, v, "%T: %v", rawValue, rawValue)
+	}
+
+	for _, rawValue := range []interface{}{"foo", "34", []int{1}} {
+		c.Set("flag", rawValue)
+
+		v, err := c.GetBoolE("flag")
+		require.ErrorIs(t, err, conf.ErrInvalidValue, "%T: %v", rawValue, rawValue)
+		require.False(t, v, "%T: %v", rawValue, rawValue)
+		require.False(t, c.GetBool("flag"), "%T: %v", rawValue, rawValue)
+	}
+}
+
+func TestConf_WithBoolValues(t *testing.T) {
+	t.Parallel()
+
+	es := conf.New().WithBoolValues(map[string]bool{"sí": true, "yes": false})
+	de := conf.New().WithBoolValues(map[string]bool{"ja": true, "nein": false})
+
+	for _, c := range []conf.Conf{es, de} {
+		c.Set("es", "sí")
+		c.Set("de", "ja")
+		c.Set("en", "yes")
+		c.Set("on", "on")
+	}
+
+	require.True(t, es.GetBool("es"))
+	require.False(t, es.GetBool("de"))
+	require.False(t, es.GetBool("en"))
+	require.True(t, es.GetBool("on"))
+
+	require.False(t, de.GetBool("es"))
+	require.True(t, de.GetBool("de"))
+	require.True(t, de.GetBool("en"))
+	require.True(t, de.GetBool("on"))
+
+	_, err := de.GetBoolE("es")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+}
+
+func TestConf_GetBool_FalseValues(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithBoolValues(map[string]bool{"nope": false, "yep": true})
+	data := map[string]bool{
+		"no":   false,
+		"No":   false,
+		"off":  false,
+		"Off":  false,
+		"n":    false,
+		"N":    false,
+		"nope": false,
+		"yep":  true,
+		"on":   true,
+	}
+	for rawValue, expectedValue := range data {
+		c.Set("flag", rawValue)
+
+		v, err := c.GetBoolE("flag")
+		require.NoError(t, err, rawValue)
+		require.Equal(t, expectedValue, v, rawValue)
+		require.Equal(t, expectedValue, c.GetBool("flag"), rawValue)
+	}
+
+	c.Set("flag", "nah")
+	_, err := c.GetBoolE("flag")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.False(t, c.GetBool("flag"))
+}
+
+func TestConf_AddReader(t *testing.T) {
+	t.Parallel()
+
+	base := newReader(t, "", map[string]interface{}{"foo": "base", "bar": "base"}, nil)
+	override := newReader(t, "", map[string]interface{}{"foo": "override"}, nil)
+	fallback := newReader(t, "", map[string]interface{}{"foo": "fallback", "baz": "fallback"}, nil)
+
+	c := conf.New().WithReaders(base).AddReader(override).PrependReader(fallback)
+	require.Equal(t, []conf.Reader{fallback, base, override}, c.Readers())
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "override", c.Get("foo"))
+	require.Equal(t, "base", c.Get("bar"))
+	require.Equal(t, "fallback", c.Get("baz"))
+
+	readers := c.Readers()
+	readers[0] = override
+	require.Equal(t, []conf.Reader{fallback, base, override}, c.Readers())
+}
+
+func TestConf_GetComplex(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	data := map[interface{}]complex128{
+		"1+2i":                   complex(1, 2),
+		"-1.5-0.5i":              complex(-1.5, -0.5),
+		"3i":                     complex(0, 3),
+		"2":                      complex(2, 0),
+		complex(1, 2):            complex(1, 2),
+		complex64(complex(3, 4)): complex(3, 4),
+		1.25:                     complex(1.25, 0),
+		-1:                       complex(-1, 0),
+		"":                       0,
+		nil:                      0,
+	}
+	for rawValue, expectedValue := range data {
+		c.Set("flag", rawValue)
+
+		v, err := c.GetComplex128E("flag")
+		require.NoError(t, err, "%T: %v", rawValue, rawValue)
+		require.Equal(t, expectedValue, v, "%T: %v", rawValue, rawValue)
+		require.Equal(t, expectedValue, c.GetComplex128("flag"), "%T: %v", rawValue, rawValue)
+
+		v64, err := c.GetComplex64E("flag")
+		require.NoError(t, err, "%T: %v", rawValue, rawValue)
+		require.Equal(t, complex64(expectedValue), v64, "%T: %v", rawValue, rawValue)
+		require.Equal(t, complex64(expectedValue), c.GetComplex64("flag"), "%T: %v", rawValue, rawValue)
+	}
+
+	c.Set("flag", "1+x")
+	_, err := c.GetComplex128E("flag")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	_, err = c.GetComplex64E("flag")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.Zero(t, c.GetComplex128("flag"))
+	require.Zero(t, c.GetComplex64("flag"))
+}
+
+type testHealthCheckReader struct {
+	testReader
+	healthErr error
+}
+
+func (t *testHealthCheckReader) HealthCheck(_ context.Context) error {
+	return t.healthErr
+}
+
+func TestConf_Ready(t *testing.T) {
+	t.Parallel()
+
+	healthy := &testHealthCheckReader{}
+	unhealthy := &testHealthCheckReader{healthErr: errFake}
+
+	require.NoError(t, conf.New().Ready(context.Background()))
+	require.NoError(t, conf.New().WithReaders(newReader(t, "", nil, nil), healthy).Ready(context.Background()))
+	require.ErrorIs(t,
+		conf.New().WithReaders(newReader(t, "", nil, nil), healthy, unhealthy).Ready(context.Background()),
+		errFake,
+	)
+}
+
+func TestConf_AllSettings(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{
+			"foo": "bar",
+			"xyz": []int{1, 2, 3},
+			"a": map[string]interface{}{
+				"b": []map[string]interface{}{
+					{
+						"c": 1,
+					},
+					{
+						"d": 2,
+					},
+				},
+			},
+			"x.y.z": "xyz",
+		}, nil),
+		newReader(t, "data4", []interface{}{1, "2"}, nil),
+	).WithTransformers(testTransform)
+	c.SetDefault("db.host", "localhost")
+	c.SetDefault("foo", "default")
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("a.b.1.d", "value-to-be-transformed")
+
+	expected := map[string]interface{}{
+		"foo": "bar",
+		"xyz": []interface{}{1, 2, 3},
+		"a": map[string]interface{}{
+			"b": []interface{}{
+				map[string]interface{}{"c": 1},
+				map[string]interface{}{"d": 101},
+			},
+		},
+		"x":     map[string]interface{}{"y": map[string]interface{}{"z": "xyz"}},
+		"data4": []interface{}{1, "2"},
+		"db":    map[string]interface{}{"host": "localhost"},
+	}
+	require.Equal(t, expected, c.AllSettings())
+	require.Empty(t, conf.New().AllSettings())
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"foo": "bar",
+		"xyz": [1, 2, 3],
+		"a": {"b": [{"c": 1}, {"d": 101}]},
+		"x": {"y": {"z": "xyz"}},
+		"data4": [1, "2"],
+		"db": {"host": "localhost"}
+	}`, string(data))
+}
+
+func TestConf_GetIntE(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("int8", 127)
+	c.Set("int16", "-32768")
+	c.Set("int32", 2147483647.0)
+	c.Set("int64", "9223372036854775807")
+	c.Set("invalid", "foo")
+
+	v8, err := c.GetInt8E("int8")
+	require.NoError(t, err)
+	require.Equal(t, int8(127), v8)
+	v16, err := c.GetInt16E("int16")
+	require.NoError(t, err)
+	require.Equal(t, int16(-32768), v16)
+	v32, err := c.GetInt32E("int32")
+	require.NoError(t, err)
+	require.Equal(t, int32(2147483647), v32)
+	v64, err := c.GetInt64E("int64")
+	require.NoError(t, err)
+	require.Equal(t, int64(9223372036854775807), v64)
+	v, err := c.GetIntE("int32")
+	require.NoError(t, err)
+	require.Equal(t, 2147483647, v)
+
+	c.Set("int8", 300)
+	c.Set("int16", "-32769")
+	c.Set("int32", 2147483648.0)
+
+	v8, err = c.GetInt8E("int8")
+	require.ErrorIs(t, err, conf.ErrOverflow)
+	require.Zero(t, v8)
+	v16, err = c.GetInt16E("int16")
+	require.ErrorIs(t, err, conf.ErrOverflow)
+	require.Zero(t, v16)
+	v32, err = c.GetInt32E("int32")
+	require.ErrorIs(t, err, conf.ErrOverflow)
+	require.Zero(t, v32)
+
+	_, err = c.GetIntE("invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	_, err = c.GetInt8E("invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	_, err = c.GetInt64E("invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+}
+
+func TestConf_WithRequiredKeys(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().
+		WithReaders(newReader(t, "", map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}}, nil)).
+		WithRequiredKeys("db.host", "db.port", "db.user", "name")
+	c.SetDefault("name", "app")
+	require.EqualError(t, c.Load(context.Background()), "missing required keys: db.port, db.user")
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrMissingKeys)
+
+	c.SetDefault("db.port", 5432)
+	c.SetDefault("db.user", "postgres")
+	require.NoError(t, c.Load(context.Background()))
+}
+
+func TestConf_WithReadOptimized(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{"foo": 42}, nil)).WithReadOptimized()
+	c.SetDefault("bar", 101)
+	require.Nil(t, c.Get("foo"))
+	require.Equal(t, 101, c.Get("bar"))
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 42, c.Get("foo"))
+
+	c.Set("foo", 43)
+	require.Equal(t, 43, c.Get("foo"))
+
+	c.Reset()
+	require.Nil(t, c.Get("foo"))
+	require.Equal(t, 101, c.Get("bar"))
+}
+
+func TestConf_ConcurrentLoad(t *testing.T) {
+	t.Parallel()
+
+	for name, c := range map[string]conf.Conf{
+		"default":        conf.New(),
+		"read optimized": conf.New().WithReadOptimized(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c.WithReaders(newReader(t, "", map[string]interface{}{"foo": 42, "bar": []int{1, 2}}, nil))
+			require.NoError(t, c.Load(context.Background()))
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for j := 0; j < 100; j++ {
+						if v := c.Get("foo"); v != nil {
+							assert.Equal(t, 42, v)
+						}
+						c.Get("bar.1")
+					}
+				}()
+			}
+			for j := 0; j < 100; j++ {
+				require.NoError(t, c.Load(context.Background()))
+				c.Set("baz", j)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	data := map[string]interface{}{}
+	for i := 0; i < 100; i++ {
+		data["key"+strconv.Itoa(i)] = i
+	}
+
+	for name, c := range map[string]conf.Conf{
+		"default":        conf.New(),
+		"read optimized": conf.New().WithReadOptimized(),
+	} {
+		b.Run(name, func(b *testing.B) {
+			c.WithReaders(newReader(b, "", data, nil))
+			require.NoError(b, c.Load(context.Background()))
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Get("key" + strconv.Itoa(i%100))
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestConf_GetStringMapBool(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithBoolValues(map[string]bool{"enabled": true, "disabled": false}).WithReaders(
+		newReader(t, "features", map[string]interface{}{
+			"a": true,
+			"b": false,
+			"c": "yes",
+			"d": "off",
+			"e": "enabled",
+			"f": "disabled",
+			"g": 1,
+			"h": "foo",
+		}, nil),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("features.b", "on")
+
+	require.Equal(t, map[string]bool{
+		"a": true,
+		"b": true,
+		"c": true,
+		"d": false,
+		"e": true,
+		"f": false,
+		"g": true,
+		"h": false,
+	}, c.GetStringMapBool("features"))
+	require.Nil(t, c.GetStringMapBool("features.a"))
+	require.Nil(t, c.GetStringMapBool("no key"))
+}
+
+func TestConf_GetObject(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []map[string]interface{}{
+				{
+					"c": 1,
+				},
+				{
+					"d": 2,
+				},
+			},
+		},
+		"xyz": []int{1, 2, 3},
+		"foo": "bar",
+	}, nil))
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("a.b.0.c", 5)
+
+	type item struct {
+		C int
+		D int `json:"d"`
+	}
+	var a struct {
+		B []item `json:"b"`
+	}
+	require.NoError(t, c.GetObject("a", &a))
+	require.Equal(t, []item{{C: 5}, {D: 2}}, a.B)
+
+	var m map[string]interface{}
+	require.NoError(t, c.GetObject("a.b.1", &m))
+	require.Equal(t, map[string]interface{}{"d": float64(2)}, m)
+
+	var s []int
+	require.NoError(t, c.GetObject("xyz", &s))
+	require.Equal(t, []int{1, 2, 3}, s)
+
+	var str string
+	require.NoError(t, c.GetObject("foo", &str))
+	require.Equal(t, "bar", str)
+
+	require.ErrorIs(t, c.GetObject("no key", &m), conf.ErrKeyNotFound)
+	require.ErrorIs(t, c.GetObject("foo", &a), conf.ErrInvalidValue)
+}
+
+func TestConf_WithStrictCast(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lenient", func(t *testing.T) {
+		t.Parallel()
+
+		c := conf.New()
+		c.Set("foo", "bar")
+		require.Zero(t, c.GetInt("foo"))
+		require.False(t, c.GetBool("foo"))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		c := conf.New().WithStrictCast(nil)
+		c.Set("foo", "bar")
+		c.Set("baz", "42")
+		require.Equal(t, 42, c.GetInt("baz"))
+		require.Zero(t, c.GetInt("no key"))
+		require.PanicsWithError(t, `invalid value "foo": unable to cast "bar" of type string to int64`, func() {
+			c.GetInt("foo")
+		})
+		require.Panics(t, func() {
+			c.GetBool("foo")
+		})
+		require.Panics(t, func() {
+			c.GetDuration("foo")
+		})
+		require.Panics(t, func() {
+			c.GetComplex128("foo")
+		})
+	})
+
+	t.Run("logger", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		c := conf.New().WithStrictCast(slog.New(slog.NewTextHandler(&buf, nil)))
+		c.Set("foo", "bar")
+		require.Zero(t, c.GetFloat64("foo"))
+		require.Contains(t, buf.String(), `level=ERROR msg="failed to cast configuration value" key=foo`)
+	})
+}
+
+func TestSetGlobalConf(t *testing.T) {
+	old := conf.GlobalConf()
+	t.Cleanup(func() {
+		conf.SetGlobalConf(old)
+	})
+
+	c := conf.New()
+	conf.SetGlobalConf(c)
+	require.Same(t, c, conf.GlobalConf())
+	conf.Set("foo", 42)
+	require.Equal(t, 42, c.Get("foo"))
+	require.Nil(t, old.Get("foo"))
+
+	conf.SetGlobalConf(nil)
+	require.NotNil(t, conf.GlobalConf())
+	require.NotSame(t, c, conf.GlobalConf())
+	require.Nil(t, conf.Get("foo"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				conf.Set("foo", j)
+				conf.GetInt("foo")
+				conf.Keys()
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		conf.SetGlobalConf(conf.New())
+	}
+	wg.Wait()
+}
+
+func TestConf_WithDecimalSeparator(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("flag", "1,25")
+	require.Zero(t, c.GetFloat64("flag"))
+
+	c.WithDecimalSeparator(',')
+	data := map[interface{}]float64{
+		"1,25":  1.25,
+		"-0,5":  -0.5,
+		"42":    42,
+		1.5:     1.5,
+		"":      0,
+		"1,2,3": 0,
+	}
+	for rawValue, expectedValue := range data {
+		c.Set("flag", rawValue)
+
+		require.Equal(t, expectedValue, c.GetFloat64("flag"), "%T: %v", rawValue, rawValue)
+		require.Equal(t, float32(expectedValue), c.GetFloat32("flag"), "%T: %v", rawValue, rawValue)
+	}
+}
+
+func TestConf_ResetPrefix(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": 5432,
+		},
+		"dbx":  "foo",
+		"name": "app",
+	}, nil))
+	c.SetDefault("db.host", "default")
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.Get("db.host"))
+
+	c.ResetPrefix("db")
+	require.Equal(t, "default", c.Get("db.host"))
+	require.Nil(t, c.Get("db.port"))
+	require.Nil(t, c.Get("db"))
+	require.Equal(t, "foo", c.Get("dbx"))
+	require.Equal(t, "app", c.Get("name"))
+	require.ElementsMatch(t, []string{"dbx", "name"}, c.OrderedKeys(""))
+	require.ElementsMatch(t, []string{"dbx", "name", "db.host"}, c.Keys())
+}
+
+func TestConf_Walk(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithTransformers(testTransform).WithReaders(newReader(t, "", map[string]interface{}{
+		"foo": "value-to-be-transformed",
+		"bar": []int{1, 2},
+	}, nil))
+	c.SetDefault("foo", "default")
+	c.SetDefault("baz", 3)
+	require.NoError(t, c.Load(context.Background()))
+
+	var keys []string
+	values := map[string]interface{}{}
+	c.Walk(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		values[key] = value
+		return true
+	})
+	require.Equal(t, []string{"bar", "bar.0", "bar.1", "baz", "foo"}, keys)
+	require.Equal(t, map[string]interface{}{
+		"bar":   []int{1, 2},
+		"bar.0": 1,
+		"bar.1": 2,
+		"baz":   3,
+		"foo":   101,
+	}, values)
+
+	keys = nil
+	c.Walk(func(key string, _ interface{}) bool {
+		keys = append(keys, key)
+		return len(keys) < 2
+	})
+	require.Equal(t, []string{"bar", "bar.0"}, keys)
+}
+
+func TestConf_ConcurrentKeys(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{}
+	for i := 0; i < 10; i++ {
+		data["key"+strconv.Itoa(i)] = i
+	}
+	c := conf.New().WithReaders(newReader(t, "", data, nil))
+	require.NoError(t, c.Load(context.Background()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				keys := c.Keys()
+				assert.Len(t, keys, len(data))
+				n := 0
+				c.Walk(func(_ string, _ interface{}) bool {
+					n++
+					return true
+				})
+				assert.Equal(t, len(data), n)
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		require.NoError(t, c.Load(context.Background()))
+	}
+	wg.Wait()
+}
+
+func TestConf_GetD(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("str", "foo")
+	c.Set("map", map[string]interface{}{"foo": "bar"})
+	c.Set("int", "42")
+	c.Set("big", 300)
+	c.Set("bool", "nope")
+	c.Set("float", "1.5")
+	c.Set("duration", "1s")
+	c.Set("time", "2024-01-02T03:04:05Z")
+	c.Set("complex", "1+2i")
+
+	require.Equal(t, "foo", c.GetStringD("str", "def"))
+	require.Equal(t, "def", c.GetStringD("missing", "def"))
+	require.Equal(t, "def", c.GetStringD("map", "def"))
+
+	require.Equal(t, 42, c.GetIntD("int", 7))
+	require.Equal(t, 7, c.GetIntD("missing", 7))
+	require.Equal(t, 7, c.GetIntD("str", 7))
+	require.Equal(t, int8(7), c.GetInt8D("big", 7))
+	require.Equal(t, int16(300), c.GetInt16D("big", 7))
+	require.Equal(t, int32(7), c.GetInt32D("str", 7))
+	require.Equal(t, int64(42), c.GetInt64D("int", 7))
+
+	require.True(t, c.GetBoolD("missing", true))
+	require.True(t, c.GetBoolD("bool", true))
+	require.False(t, c.GetBoolD("bool", false))
+
+	require.InDelta(t, float32(1.5), c.GetFloat32D("float", 2), 0)
+	require.InDelta(t, 2.0, c.GetFloat64D("str", 2), 0)
+
+	require.Equal(t, complex64(1+2i), c.GetComplex64D("complex", 3))
+	require.Equal(t, complex128(3), c.GetComplex128D("str", 3))
+
+	require.Equal(t, time.Second, c.GetDurationD("duration", time.Minute))
+	require.Equal(t, time.Minute, c.GetDurationD("str", time.Minute))
+
+	def := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), c.GetTimeD("time", def))
+	require.Equal(t, def, c.GetTimeD("missing", def))
+	require.Equal(t, def, c.GetTimeD("str", def))
+}
+
+func TestConf_OnSecretRotation(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{"db": map[string]interface{}{"password": "old", "user": "admin"}}
+	r := &testReader{data: data}
+
+	var rotated []interface{}
+	c := conf.New().WithReaders(r).OnSecretRotation("db.password", func(newValue interface{}) {
+		rotated = append(rotated, newValue)
+	})
+	require.NoError(t, c.Load(context.Background()))
+	require.Empty(t, rotated)
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Empty(t, rotated)
+
+	r.data = map[string]interface{}{"db": map[string]interface{}{"password": "new", "user": "root"}}
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, []interface{}{"new"}, rotated)
+
+	r.data = map[string]interface{}{}
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, []interface{}{"new"}, rotated)
+}
+
+func TestConf_UnmarshalMap(t *testing.T) {
+	t.Parallel()
+
+	type plugin struct {
+		Path    string `json:"path"`
+		Enabled bool   `json:"enabled"`
+	}
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"plugins": map[string]interface{}{
+			"a": map[string]interface{}{"path": "/usr/lib/a.so", "enabled": true},
+			"b": map[string]interface{}{"path": "/usr/lib/b.so"},
+		},
+	}, nil))
+	require.NoError(t, c.Load(context.Background()))
+
+	var plugins map[string]plugin
+	require.NoError(t, c.UnmarshalMap("plugins", &plugins))
+	require.Equal(t, map[string]plugin{
+		"a": {Path: "/usr/lib/a.so", Enabled: true},
+		"b": {Path: "/usr/lib/b.so"},
+	}, plugins)
+
+	ptrs := map[string]*plugin{"c": {}}
+	require.NoError(t, c.UnmarshalMap("plugins", &ptrs))
+	require.Len(t, ptrs, 3)
+	require.Equal(t, &plugin{Path: "/usr/lib/b.so"}, ptrs["b"])
+
+	require.ErrorIs(t, c.UnmarshalMap("no key", &plugins), conf.ErrKeyNotFound)
+	require.ErrorIs(t, c.UnmarshalMap("plugins.a.path", &plugins), conf.ErrInvalidValue)
+	require.ErrorIs(t, c.UnmarshalMap("plugins", plugins), conf.ErrInvalidValue)
+
+	var wrong map[string]int
+	require.ErrorIs(t, c.UnmarshalMap("plugins", &wrong), conf.ErrInvalidValue)
+}
+
+func TestConf_WithDurationUnit(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("string", "30")
+	c.Set("float string", "1.5")
+	c.Set("int", 30)
+	c.Set("uint", uint(2))
+	c.Set("float", 0.5)
+	c.Set("json", json.Number("10"))
+	c.Set("with unit", "1m")
+	c.Set("duration", 2*time.Millisecond)
+
+	require.Equal(t, 30*time.Nanosecond, c.GetDuration("string"))
+	require.Equal(t, 30*time.Nanosecond, c.GetDuration("int"))
+
+	c.WithDurationUnit(time.Second)
+	require.Equal(t, 30*time.Second, c.GetDuration("string"))
+	require.Equal(t, 1500*time.Millisecond, c.GetDuration("float string"))
+	require.Equal(t, 30*time.Second, c.GetDuration("int"))
+	require.Equal(t, 2*time.Second, c.GetDuration("uint"))
+	require.Equal(t, 500*time.Millisecond, c.GetDuration("float"))
+	require.Equal(t, 10*time.Second, c.GetDuration("json"))
+	require.Equal(t, time.Minute, c.GetDuration("with unit"))
+	require.Equal(t, 2*time.Millisecond, c.GetDuration("duration"))
+	require.Equal(t, 30*time.Second, c.GetDurationD("string", time.Hour))
+	require.Equal(t, time.Hour, c.GetDurationD("no key", time.Hour))
+}
+
+func TestConf_GetStringSlice(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("origins", []interface{}{"https://a.example.com", "https://b.example.com", "https://a.example.com", 42, "42"})
+	c.Set("fields", "foo bar foo")
+
+	require.Equal(t,
+		[]string{"https://a.example.com", "https://b.example.com", "https://a.example.com", "42", "42"},
+		c.GetStringSlice("origins"),
+	)
+	require.Equal(t, []string{"https://a.example.com", "https://b.example.com", "42"}, c.GetStringSliceUnique("origins"))
+	require.Equal(t, []string{"foo", "bar", "foo"}, c.GetStringSlice("fields"))
+	require.Equal(t, []string{"foo", "bar"}, c.GetStringSliceUnique("fields"))
+	require.Nil(t, c.GetStringSliceUnique("no key"))
+}
+
+func TestConf_GetRune(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("single", ";")
+	c.Set("multi", "élan")
+	c.Set("code", 44)
+	c.Set("code string", "44")
+	c.Set("empty", "")
+	c.Set("invalid", "\xff")
+	c.Set("negative", -1)
+
+	require.Equal(t, ';', c.GetRune("single"))
+	require.Equal(t, 'é', c.GetRune("multi"))
+	require.Equal(t, ',', c.GetRune("code"))
+	require.Equal(t, '4', c.GetRune("code string"))
+	require.Zero(t, c.GetRune("empty"))
+	require.Zero(t, c.GetRune("no key"))
+
+	r, err := c.GetRuneE("multi")
+	require.NoError(t, err)
+	require.Equal(t, 'é', r)
+
+	_, err = c.GetRuneE("empty")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	_, err = c.GetRuneE("invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	_, err = c.GetRuneE("negative")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+
+	require.Equal(t, byte(';'), c.GetByte("single"))
+	require.Equal(t, byte(','), c.GetByte("code"))
+	require.Zero(t, c.GetByte("empty"))
+
+	b, err := c.GetByteE("multi")
+	require.NoError(t, err)
+	require.Equal(t, byte(0xe9), b)
+
+	c.Set("wide", "€")
+	_, err = c.GetByteE("wide")
+	require.ErrorIs(t, err, conf.ErrOverflow)
+	_, err = c.GetByteE("empty")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+}
+
+func TestConf_Immutable(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("foo", "bar")
+	c.SetDefault("num", 42)
+
+	view := c.Immutable()
+	_, ok := view.(conf.Conf)
+	require.False(t, ok)
+	_, ok = view.(interface {
+		Set(key string, value interface{}) conf.Conf
+	})
+	require.False(t, ok)
+
+	require.Equal(t, "bar", view.GetString("foo"))
+	require.Equal(t, 42, view.GetInt("num"))
+	require.Equal(t, []string{"foo", "num"}, view.Keys())
+
+	c.Set("foo", "baz")
+	require.Equal(t, "baz", view.Get("foo"))
+
+	data, err := json.Marshal(view)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"foo": "baz", "num": 42}`, string(data))
+}
+
+func TestConf_GetPercent(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("percent", "10%")
+	c.Set("full", "100%")
+	c.Set("spaces", " 12.5 % ")
+	c.Set("ratio", "0.1")
+	c.Set("float", 0.25)
+	c.Set("invalid", "abc%")
+
+	require.InDelta(t, 0.1, c.GetPercent("percent"), 1e-9)
+	require.InDelta(t, 1.0, c.GetPercent("full"), 1e-9)
+	require.InDelta(t, 0.125, c.GetPercent("spaces"), 1e-9)
+	require.InDelta(t, 0.1, c.GetPercent("ratio"), 1e-9)
+	require.InDelta(t, 0.25, c.GetPercent("float"), 1e-9)
+	require.Zero(t, c.GetPercent("invalid"))
+	require.Zero(t, c.GetPercent("no key"))
+
+	c.WithDecimalSeparator(',')
+	c.Set("comma", "2,5%")
+	require.InDelta(t, 0.025, c.GetPercent("comma"), 1e-9)
+}
+
+func TestConf_GetMergedMap(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"defaults": map[string]interface{}{
+			"timeout": "1s",
+			"db":      map[string]interface{}{"host": "localhost", "port": 5432},
+		},
+		"overrides": map[string]interface{}{
+			"db":    map[string]interface{}{"host": "db.example.com"},
+			"debug": true,
+		},
+		"scalar": "foo",
+	}, nil))
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, map[string]interface{}{
+		"timeout": "1s",
+		"db":      map[string]interface{}{"host": "db.example.com", "port": 5432},
+		"debug":   true,
+	}, c.GetMergedMap("defaults", "overrides", "scalar", "no key"))
+	require.Equal(t, map[string]interface{}{
+		"timeout": "1s",
+		"db":      map[string]interface{}{"host": "localhost", "port": 5432},
+		"debug":   true,
+	}, c.GetMergedMap("overrides", "defaults"))
+	require.Empty(t, c.GetMergedMap())
+}
+
+func TestConf_Checksum(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"db":   map[string]interface{}{"host": "localhost", "port": 5432},
+		"tags": []string{"a", "b"},
+	}
+	c1 := conf.New().WithReaders(newReader(t, "", data, nil))
+	require.NoError(t, c1.Load(context.Background()))
+
+	c2 := conf.New()
+	c2.Set("tags", []string{"a", "b"})
+	c2.Set("tags.1", "b")
+	c2.Set("tags.0", "a")
+	c2.Set("db.port", 5432)
+	c2.Set("db.host", "localhost")
+	c2.Set("db", map[string]interface{}{"port": 5432, "host": "localhost"})
+
+	require.Len(t, c1.Checksum(), 64)
+	require.Equal(t, c1.Checksum(), c2.Checksum())
+
+	sum := c1.Checksum()
+	require.NoError(t, c1.Load(context.Background()))
+	require.Equal(t, sum, c1.Checksum())
+
+	c1.Set("db.port", 5433)
+	require.NotEqual(t, sum, c1.Checksum())
+
+	c2.SetDefault("debug", true)
+	require.NotEqual(t, sum, c2.Checksum())
+}
+
+func TestConf_SetDefaultFromEnv(t *testing.T) {
+	c := conf.New().
+		SetDefaultFromEnv("http.port", "CONF_TEST_PORT", 8080).
+		SetDefaultFromEnv("db.host", "CONF_TEST_DB_HOST", "localhost")
+	require.Equal(t, 8080, c.GetInt("http.port"))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+
+	t.Setenv("CONF_TEST_PORT", "9090")
+	require.Equal(t, 9090, c.GetInt("http.port"))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+
+	c.Set("http.port", 80)
+	require.Equal(t, 80, c.GetInt("http.port"))
+}
+
+func TestConf_WithListSeparator(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("spaces", "a b  c")
+	c.Set("commas", "a, b,c,")
+	c.Set("semicolons", "a;b c;d")
+	c.Set("mixed", "a,b c")
+
+	require.Equal(t, []string{"a", "b", "c"}, c.GetStringSlice("spaces"))
+	require.Equal(t, []string{"a,", "b,c,"}, c.GetStringSlice("commas"))
+	require.Equal(t, []string{"a;b", "c;d"}, c.GetStringSlice("semicolons"))
+	require.Equal(t, []string{"a,b", "c"}, c.GetStringSlice("mixed"))
+
+	c.WithListSeparator(',')
+	require.Equal(t, []string{"a", "b", "c"}, c.GetStringSlice("spaces"))
+	require.Equal(t, []string{"a", "b", "c"}, c.GetStringSlice("commas"))
+	require.Equal(t, []string{"a", "b c"}, c.GetStringSlice("mixed"))
+
+	c.WithListSeparator(';')
+	require.Equal(t, []string{"a", "b", "c"}, c.GetStringSlice("spaces"))
+	require.Equal(t, []string{"a,", "b,c,"}, c.GetStringSlice("commas"))
+	require.Equal(t, []string{"a", "b c", "d"}, c.GetStringSlice("semicolons"))
+}
+
+func TestConf_GetWithSource(t *testing.T) {
+	t.Parallel()
+
+	file := &testNamedReader{
+		Reader: newReader(t, "", map[string]interface{}{"db": map[string]interface{}{"host": "localhost"}}, nil),
+		name:   "config.json",
+	}
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{"db": map[string]interface{}{"port": 5432, "host": "example.com"}}, nil),
+		file,
+	).WithProfile("env")
+	c.SetDefault("db.user", "admin")
+	c.SetDefault("env", "test")
+	c.SetProfileDefault("test", "db.name", "testdb")
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("debug", true)
+
+	value, source := c.GetWithSource("db.host")
+	require.Equal(t, "localhost", value)
+	require.Equal(t, "reader[1] config.json", source)
+
+	value, source = c.GetWithSource("db.port")
+	require.Equal(t, 5432, value)
+	require.Equal(t, "reader[0] *conf_test.testReader", source)
+
+	value, source = c.GetWithSource("db.user")
+	require.Equal(t, "admin", value)
+	require.Equal(t, conf.SourceDefault, source)
+
+	value, source = c.GetWithSource("db.name")
+	require.Equal(t, "testdb", value)
+	require.Equal(t, conf.SourceProfileDefault, source)
+
+	value, source = c.GetWithSource("debug")
+	require.Equal(t, true, value)
+	require.Equal(t, conf.SourceSet, source)
+
+	value, source = c.GetWithSource("no key")
+	require.Nil(t, value)
+	require.Empty(t, source)
+}
+
+func TestConf_WithCollisionCheck(t *testing.T) {
+	t.Parallel()
+
+	readers := []conf.Reader{
+		newReader(t, "db", "postgres://localhost", nil),
+		newReader(t, "db.host", "localhost", nil),
+		newReader(t, "", map[string]interface{}{
+			"cache": map[string]interface{}{"host": "localhost"},
+			"log":   "debug",
+		}, nil),
+		newReader(t, "log.level", "info", nil),
+	}
+
+	c := conf.New().WithReaders(readers...)
+	require.NoError(t, c.Load(context.Background()))
+
+	c = conf.New().WithReaders(readers...).WithCollisionCheck()
+	err := c.Load(context.Background())
+	require.ErrorIs(t, err, conf.ErrKeyCollision)
+	require.EqualError(t, err, "key collision: db, log")
+	require.Equal(t, "localhost", c.GetString("db.host"))
+
+	c = conf.New().WithReaders(readers[2]).WithCollisionCheck()
+	require.NoError(t, c.Load(context.Background()))
+}
+
+func TestConf_GetLines(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("ips", "10.0.0.1\n  10.0.0.2  \r\n\n\t\n10.0.0.3\n")
+	c.Set("single", "10.0.0.1")
+	c.Set("slice", []string{"a", "b"})
+	c.Set("blank", "\n \n")
+
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, c.GetLines("ips"))
+	require.Equal(t, []string{"10.0.0.1"}, c.GetLines("single"))
+	require.Equal(t, []string{"a", "b"}, c.GetLines("slice"))
+	require.Empty(t, c.GetLines("blank"))
+	require.Empty(t, c.GetLines("no key"))
+}
+
+func TestConf_Generation(t *testing.T) {
+	t.Parallel()
+
+	r := &testReader{data: map[string]interface{}{"foo": "bar"}}
+	c := conf.New().WithReaders(r)
+	require.Zero(t, c.Generation())
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, uint64(1), c.Generation())
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, uint64(2), c.Generation())
+
+	r.err = errFake
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+	require.Equal(t, uint64(2), c.Generation())
+
+	c.Set("foo", "baz")
+	require.Equal(t, uint64(2), c.Generation())
+
+	r.err = nil
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, uint64(3), c.Generation())
+
+	c.WithRequiredKeys("bar")
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrMissingKeys)
+	require.Equal(t, uint64(3), c.Generation())
+}
+
+func testCertificate(tb testing.TB) (certPEM, keyPEM string) {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(tb, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "conf.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(tb, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(tb, err)
+
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return certPEM, keyPEM
+}
+
+func TestConf_GetCertificate(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM := testCertificate(t)
+	keyFile := filepath.Join(t.TempDir(), "tls.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(keyPEM), 0o600))
+
+	c := conf.New()
+	c.Set("tls.cert", certPEM)
+	c.Set("tls.key", keyFile)
+	c.Set("tls.missing_file", filepath.Join(t.TempDir(), "missing.pem"))
+
+	cert, err := c.GetCertificate("tls.cert", "tls.key")
+	require.NoError(t, err)
+	require.Len(t, cert.Certificate, 1)
+
+	_, err = c.GetCertificate("tls.cert", "tls.no_key")
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+
+	_, err = c.GetCertificate("tls.cert", "tls.missing_file")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = c.GetCertificate("tls.key", "tls.cert")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+}
+
+func TestConf_GetCertPool(t *testing.T) {
+	t.Parallel()
+
+	cert1, _ := testCertificate(t)
+	cert2, _ := testCertificate(t)
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(certFile, []byte(cert2), 0o600))
+
+	c := conf.New()
+	c.Set("ca.inline", cert1)
+	c.Set("ca.list", []interface{}{cert1, certFile})
+	c.Set("ca.invalid", "-----BEGIN nothing")
+
+	pool, err := c.GetCertPool("ca.inline")
+	require.NoError(t, err)
+	require.False(t, pool.Equal(x509.NewCertPool()))
+
+	pool, err = c.GetCertPool("ca.list")
+	require.NoError(t, err)
+	expected := x509.NewCertPool()
+	require.True(t, expected.AppendCertsFromPEM([]byte(cert1+cert2)))
+	require.True(t, expected.Equal(pool))
+
+	_, err = c.GetCertPool("ca.invalid")
+	require.ErrorIs(t, err, conf.ErrNoCertificates)
+
+	_, err = c.GetCertPool("no key")
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+}
+
+func TestConf_WithLoadTransformers(t *testing.T) {
+	t.Parallel()
+
+	rename := func(raw interface{}) interface{} {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return raw
+		}
+		if v, ok := m["database"]; ok {
+			delete(m, "database")
+			m["db"] = v
+		}
+		return m
+	}
+	var calls int
+	count := func(raw interface{}) interface{} {
+		calls++
+		return raw
+	}
+
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{"database": map[string]interface{}{"host": "localhost"}}, nil),
+		newReader(t, "extra", "value", nil),
+	).WithLoadTransformers(rename, count)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	require.Nil(t, c.Get("database.host"))
+	require.Equal(t, "value", c.GetString("extra"))
+	require.Equal(t, 2, calls)
+}
+
+func TestConf_GetFileContent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	c := conf.New()
+	c.Set("db.password_file", secret)
+	c.Set("db.missing_file", filepath.Join(dir, "missing"))
+	c.Set("db.invalid", map[string]interface{}{"foo": "bar"})
+
+	data, err := c.GetFileContent("db.password_file")
+	require.NoError(t, err)
+	require.Equal(t, []byte("s3cr3t\n"), data)
+
+	_, err = c.GetFileContent("db.missing_file")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorContains(t, err, "db.missing_file")
+
+	_, err = c.GetFileContent("db.invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+
+	_, err = c.GetFileContent("no key")
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+}
+
+func TestConf_WithRawKeys(t *testing.T) {
+	t.Parallel()
+
+	raw := map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"path": "/", "allow": true},
+		},
+	}
+	c := conf.New().WithReaders(
+		newReader(t, "policy", raw, nil),
+		newReader(t, "", map[string]interface{}{
+			"db":     map[string]interface{}{"host": "localhost"},
+			"plugin": map[string]interface{}{"opts": map[string]interface{}{"a": 1}},
+		}, nil),
+	).WithRawKeys("policy", "plugin.opts")
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, raw, c.Get("policy"))
+	require.Equal(t, map[string]interface{}{"a": 1}, c.Get("plugin.opts"))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	keys := c.Keys()
+	slices.Sort(keys)
+	require.Equal(t, []string{"db", "db.host", "plugin", "plugin.opts", "policy"}, keys)
+	require.Nil(t, c.Get("policy.rules.0.path"))
+}
+
+func TestConf_GetIntSlice(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithListSeparator(',')
+	c.Set("mixed", []interface{}{1, "2", int64(3), 4.0, json.Number("5")})
+	c.Set("ints", []int{1, 2})
+	c.Set("string", "1, 2, 3")
+	c.Set("fields", "4 5")
+	c.Set("invalid", []interface{}{1, "two"})
+
+	require.Equal(t, []int{1, 2, 3, 4, 5}, c.GetIntSlice("mixed"))
+	require.Equal(t, []int{1, 2}, c.GetIntSlice("ints"))
+	require.Equal(t, []int{1, 2, 3}, c.GetIntSlice("string"))
+	require.Equal(t, []int{4, 5}, c.GetIntSlice("fields"))
+	require.Nil(t, c.GetIntSlice("invalid"))
+	require.Nil(t, c.GetIntSlice("no key"))
+}
+
+func TestConf_WithKeyNormalizer(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{
+			"db": map[string]interface{}{"max": map[string]interface{}{"conns": 5}},
+		}, nil),
+		newReader(t, "db", map[string]interface{}{"max-idle": 2}, nil),
+	).WithKeyNormalizer(conf.DashToDelimiter)
+	c.SetDefault("db.max-lifetime", "1m")
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, 5, c.GetInt("db.max-conns"))
+	require.Equal(t, 5, c.GetInt("db.max.conns"))
+	require.Equal(t, 2, c.GetInt("db.max.idle"))
+	require.Equal(t, time.Minute, c.GetDuration("db.max.lifetime"))
+
+	c.Set("db.max-conns", 10)
+	require.Equal(t, 10, c.GetInt("db.max.conns"))
+	_, source := c.GetWithSource("db.max-idle")
+	require.Equal(t, "reader[1] *conf_test.testReader", source)
+
+	c = conf.New().WithKeyNormalizer(func(key string) string {
+		return strings.ToLower(conf.DashToDelimiter(key))
+	})
+	c.Set("DB.Max-Conns", 3)
+	require.Equal(t, 3, c.GetInt("db.max.conns"))
+	require.Equal(t, 3, c.GetInt("Db.MAX.conns"))
+	require.Equal(t, []string{"db.max.conns"}, c.Keys())
+}
+
+func TestConf_WithKeyNormalizer_Tree(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{
+			"db-max":      map[string]interface{}{"conns": 5, "idle": 2},
+			"plugin-opts": map[string]interface{}{"a": 1},
+			"flags":       map[string]interface{}{"debug": true},
+		}, nil),
+	).WithKeyNormalizer(conf.DashToDelimiter).WithRawKeys("plugin-opts")
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, map[string]string{"conns": "5", "idle": "2"}, c.GetStringMapString("db-max"))
+	require.Equal(t, map[string]string{"conns": "5", "idle": "2"}, c.FlatStringMap("db-max"))
+	require.Equal(t, map[string]bool{"debug": true}, c.GetStringMapBool("flags"))
+	require.ElementsMatch(t, []string{"db.max.conns", "db.max.idle"}, c.OrderedKeys("db-max"))
+
+	var opts struct {
+		A int `json:"a"`
+	}
+	require.NoError(t, c.GetObject("plugin-opts", &opts))
+	require.Equal(t, 1, opts.A)
+	require.Equal(t, map[string]interface{}{"a": 1}, c.Get("plugin.opts"))
+	require.Nil(t, c.Get("plugin.opts.a"))
+	require.Equal(t, map[string]interface{}{
+		"db":     map[string]interface{}{"max": map[string]interface{}{"conns": 5, "idle": 2}},
+		"flags":  map[string]interface{}{"debug": true},
+		"plugin": map[string]interface{}{"opts": map[string]interface{}{"a": 1}},
+	}, c.AllSettings())
+
+	c.ResetPrefix("db-max")
+	require.Nil(t, c.Get("db.max.conns"))
+	require.Nil(t, c.GetStringMapString("db-max"))
+}
+
+func TestConf_Dump(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "db", map[string]interface{}{"host": "db.example.com"}, nil),
+	).WithTransformers(conf.Redact(func(key string, value interface{}) interface{} {
+		if key == "db.password" {
+			return "*****"
+		}
+		return value
+	}))
+	c.SetDefault("db.host", "localhost")
+	c.SetDefault("db.port", 5432)
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("db.password", "secret")
+
+	var buf bytes.Buffer
+	c.Dump(&buf)
+	require.Equal(t, `db = map[host:db.example.com] (reader[0] *conf_test.testReader)
+db.host = db.example.com (reader[0] *conf_test.testReader, overrides default)
+db.password = ***** (set)
+db.port = 5432 (default)
+`, buf.String())
+}
+
+func TestConf_LoadDefaults(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "db", map[string]interface{}{"host": "db.example.com"}, nil))
+	c.SetDefault("db.user", "admin")
+	require.NoError(t, c.LoadDefaults(context.Background(),
+		newReader(t, "db", map[string]interface{}{"host": "localhost", "port": 5432}, nil),
+	))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+	require.Equal(t, "admin", c.GetString("db.user"))
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	c.Set("db.port", 6432)
+	require.Equal(t, 6432, c.GetInt("db.port"))
+	c.Reset()
+	require.Equal(t, 5432, c.GetInt("db.port"))
+
+	err := c.LoadDefaults(context.Background(),
+		newReader(t, "db", map[string]interface{}{"port": 1}, nil),
+		newReader(t, "", nil, errFake),
+	)
+	require.ErrorIs(t, err, errFake)
+	require.Equal(t, 5432, c.GetInt("db.port"))
+}
+
+func TestConf_GetStringSlice_Quoted(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithListSeparator(',')
+	c.Set("quoted", `"a,b","c"`)
+	c.Set("mixed", `x, "y, z",  w`)
+	c.Set("escaped", `"say ""hi"", bob",end`)
+	c.Set("single", `"a b"`)
+	c.Set("semicolons", `"a;b";c`)
+	c.Set("invalid", `"a,b`)
+	c.Set("inner", `a "b" c`)
+	c.Set("spaces", `"a b" "c d"`)
+	c.Set("inner separated", `x "y" z, w`)
+	c.Set("ints", `"1", 2`)
+
+	require.Equal(t, []string{"a,b", "c"}, c.GetStringSlice("quoted"))
+	require.Equal(t, []string{"x", "y, z", "w"}, c.GetStringSlice("mixed"))
+	require.Equal(t, []string{`say "hi", bob`, "end"}, c.GetStringSlice("escaped"))
+	require.Equal(t, []string{"a b"}, c.GetStringSlice("single"))
+	require.Nil(t, c.GetStringSlice("invalid"))
+	require.Equal(t, []string{"a", "b", "c"}, c.GetStringSlice("inner"))
+	require.Equal(t, []string{"a b", "c d"}, c.GetStringSlice("spaces"))
+	require.Equal(t, []string{`x "y" z`, "w"}, c.GetStringSlice("inner separated"))
+	require.Equal(t, []int{1, 2}, c.GetIntSlice("ints"))
+
+	c.WithListSeparator(';')
+	require.Equal(t, []string{"a;b", "c"}, c.GetStringSlice("semicolons"))
+
+	c = conf.New()
+	c.Set("spaces", "\"a  b\"\t c\n\"d,e\"")
+	require.Equal(t, []string{"a  b", "c", "d,e"}, c.GetStringSlice("spaces"))
+}
+
+// testIntGrid rebuilds a slice of int slices from the indexed keys under a given key
+func testIntGrid(tb testing.TB, c conf.Conf, key string) [][]int {
+	tb.Helper()
+
+	var res [][]int
+	for i := 0; c.Get(key+"."+strconv.Itoa(i)) != nil; i++ {
+		var row []int
+		for j := 0; ; j++ {
+			value := c.Get(key + "." + strconv.Itoa(i) + "." + strconv.Itoa(j))
+			if value == nil {
+				break
+			}
+			row = append(row, cast.ToInt(value))
+		}
+		res = append(res, row)
+	}
+
+	return res
+}
+
+func TestConf_NestedSlices(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "", map[string]interface{}{
+			"matrix": [][]int{{1, 2}, {3, 4}},
+			"nested": map[string]interface{}{
+				"grid": []interface{}{[]interface{}{5, "6"}, []interface{}{7}},
+			},
+		}, nil),
+	)
+	require.NoError(t, c.Load(context.Background()))
+
+	require.Equal(t, 1, c.GetInt("matrix.0.0"))
+	require.Equal(t, 2, c.GetInt("matrix.0.1"))
+	require.Equal(t, 3, c.GetInt("matrix.1.0"))
+	require.Equal(t, 4, c.GetInt("matrix.1.1"))
+	require.Nil(t, c.Get("matrix.2"))
+	require.Equal(t, []int{3, 4}, c.GetIntSlice("matrix.1"))
+	require.Equal(t, 6, c.GetInt("nested.grid.0.1"))
+	require.Equal(t, []int{7}, c.GetIntSlice("nested.grid.1"))
+
+	require.Equal(t, [][]int{{1, 2}, {3, 4}}, testIntGrid(t, c, "matrix"))
+	require.Equal(t, [][]int{{5, 6}, {7}}, testIntGrid(t, c, "nested.grid"))
+	require.Equal(t, []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}}, c.AllSettings()["matrix"])
+}
+
+func TestConf_TypedNilMap(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		newReader(t, "db", map[string]interface{}(nil), nil),
+		newReader(t, "", map[string]interface{}{"cache": map[string]int(nil)}, nil),
+		newReader(t, "", map[string]interface{}(nil), nil),
+	)
+	require.NoError(t, c.Load(context.Background()))
+
+	keys := c.Keys()
+	slices.Sort(keys)
+	require.Equal(t, []string{"cache", "db"}, keys)
+	require.Nil(t, c.Get("db"))
+	require.Nil(t, c.Get("cache"))
+	_, isMap := c.Get("db").(map[string]interface{})
+	require.False(t, isMap, "typed nil map must be stored as nil")
+	value, source := c.GetWithSource("db")
+	require.Nil(t, value)
+	require.Equal(t, "reader[0] *conf_test.testReader", source)
+}
+
+func TestConf_WithAtomicSlices(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"xyz":   []int{1, 2, 3},
+		"hosts": []interface{}{map[string]interface{}{"name": "a"}},
+	}
+	c := conf.New().WithReaders(newReader(t, "", data, nil))
+	require.NoError(t, c.Load(context.Background()))
+	require.Contains(t, c.Keys(), "xyz.0")
+	require.Equal(t, "a", c.GetString("hosts.0.name"))
+
+	c = conf.New().WithReaders(newReader(t, "", data, nil)).WithAtomicSlices()
+	require.NoError(t, c.Load(context.Background()))
+	keys := c.Keys()
+	slices.Sort(keys)
+	require.Equal(t, []string{"hosts", "xyz"}, keys)
+	require.Equal(t, []int{1, 2, 3}, c.Get("xyz"))
+	require.Equal(t, []int{1, 2, 3}, c.GetIntSlice("xyz"))
+	require.Nil(t, c.Get("xyz.0"))
+	require.Nil(t, c.Get("hosts.0.name"))
+}
+
+func TestConf_FlatStringMap(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "", map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []interface{}{
+				map[string]interface{}{"c": 1},
+				map[string]interface{}{"c": true},
+			},
+			"d": "foo",
+		},
+		"ab": "bar",
+	}, nil))
+	c.SetDefault("a.e", 1.5)
+	require.NoError(t, c.Load(context.Background()))
+
+	expected := map[string]string{"b.0.c": "1", "b.1.c": "true", "d": "foo", "e": "1.5"}
+	require.Equal(t, expected, c.FlatStringMap("a"))
+	require.Equal(t, map[string]string{"0.c": "1", "1.c": "true"}, c.FlatStringMap("a.b"))
+	require.Equal(t, map[string]string{
+		"a.b.0.c": "1", "a.b.1.c": "true", "a.d": "foo", "a.e": "1.5", "ab": "bar",
+	}, c.FlatStringMap(""))
+	require.Nil(t, c.FlatStringMap("a.d"))
+	require.Nil(t, c.FlatStringMap("no key"))
+}
+
+func TestConf_Load_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls []string
+	c := conf.New().WithReaders(
+		conf.NewFuncReader("first", func(context.Context) (interface{}, error) {
+			calls = append(calls, "first")
+			cancel()
+			return 1, nil
+		}),
+		conf.NewFuncReader("second", func(context.Context) (interface{}, error) {
+			calls = append(calls, "second")
+			return 2, nil
+		}),
+	).WithCollectLoadErrors()
+
+	require.ErrorIs(t, c.Load(ctx), context.Canceled)
+	require.Equal(t, []string{"first"}, calls)
+	require.Equal(t, 1, c.GetInt("first"))
+	require.Nil(t, c.Get("second"))
+
+	require.ErrorIs(t, c.Load(ctx), context.Canceled)
+	require.Equal(t, []string{"first"}, calls)
+	require.Equal(t, uint64(0), c.Generation())
+}
+
+func TestConf_Load_ReadConfiguration(t *testing.T) {
+	t.Parallel()
+
+	var c conf.Conf
+	var keys, settings interface{}
+	c = conf.New().WithReaders(
+		conf.NewStaticReader("", map[string]interface{}{"foo": 1}),
+		conf.NewFuncReader("bar", func(context.Context) (interface{}, error) {
+			keys = c.Keys()
+			return c.GetInt("foo") + 1, nil
+		}),
+	).WithLoadTransformers(func(raw interface{}) interface{} {
+		settings = c.AllSettings()
+		return raw
+	})
+
+	done := make(chan error)
+	go func() { done <- c.Load(context.Background()) }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Load is blocked by the readers reading the configuration")
+	}
+	require.Equal(t, 1, c.GetInt("bar"))
+	require.Empty(t, keys)
+	require.Empty(t, settings)
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 2, c.GetInt("bar"))
+	require.ElementsMatch(t, []string{"foo", "bar"}, keys)
+	require.Equal(t, map[string]interface{}{"foo": 1, "bar": 1}, settings)
+}
+
+func TestConf_Load_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var active, overlaps, calls atomic.Int32
+	c := conf.New().WithReaders(
+		conf.NewFuncReader("calls", func(context.Context) (interface{}, error) {
+			if active.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			defer active.Add(-1)
+			time.Sleep(time.Millisecond)
+			return calls.Add(1), nil
+		}),
+	).WithIncrementalLoad()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Load(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Zero(t, overlaps.Load())
+	require.Equal(t, int32(10), c.Get("calls"))
+	require.Equal(t, uint64(10), c.Generation())
+}
+
+func TestConf_GetInterpolated(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.SetDefault("db.port", 5432)
+	c.Set("db.host", "db.example.com")
+	c.Set("db.addr", "${db.host}:${db.port}")
+	c.Set("db.dsn", "postgres://${db.addr}/app")
+	c.Set("missing", "${no.key}/x")
+	c.Set("cycle.a", "a-${cycle.b}")
+	c.Set("cycle.b", "b-${cycle.a}")
+	c.Set("plain", "$HOME {x}")
+
+	require.Equal(t, "db.example.com:5432", c.GetInterpolated("db.addr"))
+	require.Equal(t, "postgres://db.example.com:5432/app", c.GetInterpolated("db.dsn"))
+	require.Equal(t, "${db.host}:${db.port}", c.GetString("db.addr"))
+	require.Equal(t, "${no.key}/x", c.GetInterpolated("missing"))
+	require.Equal(t, "$HOME {x}", c.GetInterpolated("plain"))
+	require.Empty(t, c.GetInterpolated("no key"))
+
+	value := c.GetInterpolated("cycle.a")
+	require.True(t, strings.HasPrefix(value, "a-b-a-b-"), value)
+	require.Equal(t, conf.MaxInterpolationDepth+1, strings.Count(value, "-"))
+}
+
+func TestConf_GetStringMapString(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "labels", map[string]interface{}{"team": "core", "tier": 1}, nil))
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("scalar", "foo")
+
+	require.Equal(t, map[string]string{"team": "core", "tier": "1"}, c.GetStringMapString("labels"))
+	require.Nil(t, c.GetStringMapString("scalar"))
+	require.Nil(t, c.GetStringMapString("no key"))
+
+	def := map[string]string{"team": "platform", "env": "production"}
+	require.Equal(t,
+		map[string]string{"team": "core", "tier": "1", "env": "production"},
+		c.GetStringMapStringD("labels", def),
+	)
+	require.Equal(t, map[string]string{"team": "platform", "env": "production"}, def)
+	require.Equal(t, def, c.GetStringMapStringD("no key", def))
+	require.Equal(t, map[string]string{"team": "core", "tier": "1"}, c.GetStringMapStringD("labels", nil))
+	require.Nil(t, c.GetStringMapStringD("no key", nil))
+}
+
+func TestConf_GetAll(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(newReader(t, "db", map[string]interface{}{"host": "localhost"}, nil)).
+		WithTransformers(func(key string, value interface{}, _ conf.Conf) interface{} {
+			if key == "db.password" {
+				return "***"
+			}
+			return value
+		})
+	c.SetDefault("db.port", 5432)
+	require.NoError(t, c.Load(context.Background()))
+	c.Set("db.password", "secret")
+
+	all := c.GetAll()
+	require.Equal(t, map[string]interface{}{
+		"db":          map[string]interface{}{"host": "localhost"},
+		"db.host":     "localhost",
+		"db.port":     5432,
+		"db.password": "***",
+	}, all)
+
+	all["db.host"] = "changed"
+	delete(all, "db.port")
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+
+	c.Set("db.host", "db.example.com")
+	require.Equal(t, "db.example.com", c.GetAll()["db.host"])
+	require.Equal(t, map[string]interface{}{}, conf.New().GetAll())
+}
+
+func TestConf_WithIncrementalLoad(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{"host": "localhost", "port": 5432, "tags": []string{"a"}}
+	c := conf.New().WithReaders(
+		conf.NewFuncReader("db", func(context.Context) (interface{}, error) { return data, nil }),
+	).WithIncrementalLoad()
+
+	var changes []string
+	c.OnChange(func(key string, change conf.Change) {
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", key, change.Old, change.New))
+	})
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, []string{
+		"db: <nil> -> map[host:localhost port:5432 tags:[a]]",
+		"db.host: <nil> -> localhost",
+		"db.port: <nil> -> 5432",
+		"db.tags: <nil> -> [a]",
+		"db.tags.0: <nil> -> a",
+	}, changes)
+	tags := c.Get("db.tags").([]string)
+
+	changes = nil
+	require.NoError(t, c.Load(context.Background()))
+	require.Empty(t, changes)
+
+	data = map[string]interface{}{"host": "db.example.com", "tags": []string{"a"}}
+	changes = nil
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, []string{
+		"db: map[host:localhost port:5432 tags:[a]] -> map[host:db.example.com tags:[a]]",
+		"db.host: localhost -> db.example.com",
+		"db.port: 5432 -> <nil>",
+	}, changes)
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	require.Nil(t, c.Get("db.port"))
+	require.Same(t, &tags[0], &c.Get("db.tags").([]string)[0])
+	require.ElementsMatch(t, []string{"db", "db.host", "db.tags", "db.tags.0"}, c.OrderedKeys(""))
+	require.Equal(t, uint64(3), c.Generation())
+}
+
+func TestConf_OnChange(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{"a": 1, "b": 2}
+	var failure error
+	c := conf.New().WithReaders(
+		conf.NewFuncReader("", func(context.Context) (interface{}, error) { return data, nil }),
+		conf.NewFuncReader("d", func(context.Context) (interface{}, error) { return 5, failure }),
+	).WithCollectLoadErrors()
+	require.NoError(t, c.Load(context.Background()))
+
+	changes := map[string]conf.Change{}
+	c.OnChange(func(key string, change conf.Change) {
+		changes[key] = change
+	})
+	require.NoError(t, c.Load(context.Background()))
+	require.Empty(t, changes)
+
+	data = map[string]interface{}{"a": 1, "b": 3, "c": 4}
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, map[string]conf.Change{
+		"b": {Old: 2, New: 3},
+		"c": {New: 4},
+	}, changes)
+
+	data = map[string]interface{}{"a": 2, "b": 3, "c": 4}
+	failure = errFake
+	changes = map[string]conf.Change{}
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+	require.Equal(t, map[string]conf.Change{
+		"a": {Old: 1, New: 2},
+		"d": {Old: 5},
+	}, changes)
+	require.Equal(t, 2, c.GetInt("a"))
+	require.Nil(t, c.Get("d"))
+
+	c.WithIncrementalLoad()
+	data = map[string]interface{}{"a": 3}
+	changes = map[string]conf.Change{}
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+	require.Empty(t, changes)
+	require.Equal(t, 2, c.GetInt("a"))
+}
+
+func TestConf_WithAutomaticEnv(t *testing.T) {
+	t.Setenv("CONF_AUTO_BAR_BAZ", "from env")
+	t.Setenv("CONF_AUTO_MAX_CONNS", "10")
+	t.Setenv("BAR_BAZ", "no prefix")
+
+	parser, err := conf.NewFileParser("testdata/data.json")
+	require.NoError(t, err)
+	c := conf.New().WithReaders(parser.WithParser(conf.JSONParseFunc))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "qux", c.GetString("bar.baz"))
+
+	c.WithAutomaticEnv("CONF_AUTO_")
+	c.SetDefault("max-conns", 5)
+	require.Equal(t, "from env", c.GetString("bar.baz"))
+	require.Equal(t, 10, c.GetInt("max-conns"))
+	require.Equal(t, 1, c.GetInt("foo"))
+	value, source := c.GetWithSource("bar.baz")
+	require.Equal(t, "from env", value)
+	require.Equal(t, conf.SourceEnv, source)
+
+	t.Setenv("CONF_AUTO_FOO", "2")
+	require.Equal(t, 2, c.GetInt("foo"))
+
+	c.WithAutomaticEnv("")
+	require.Equal(t, "no prefix", c.GetString("bar.baz"))
+	require.Equal(t, 5, c.GetInt("max-conns"))
+
+	c = conf.New().
+		WithReaders(conf.NewEnvReader("CONF_AUTO_")).
+		WithKeyNormalizer(conf.DashToDelimiter).
+		WithAutomaticEnv("CONF_AUTO_")
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 10, c.GetInt("max-conns"))
+	require.Equal(t, 10, c.GetInt("max.conns"))
+	require.Equal(t, "from env", c.GetString("bar.baz"))
+}
+
+func TestConf_GetPtr(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("string", "foo")
+	c.Set("empty", "")
+	c.Set("int", "42")
+	c.Set("zero", 0)
+	c.Set("bool", "true")
+	c.Set("false", false)
+	c.Set("invalid", "bar")
+	c.SetDefault("default", 7)
+
+	require.Equal(t, "foo", *c.GetStringPtr("string"))
+	require.Empty(t, *c.GetStringPtr("empty"))
+	require.Nil(t, c.GetStringPtr("no key"))
+
+	require.Equal(t, 42, *c.GetIntPtr("int"))
+	require.Zero(t, *c.GetIntPtr("zero"))
+	require.Equal(t, 7, *c.GetIntPtr("default"))
+	require.Nil(t, c.GetIntPtr("invalid"))
+	require.Nil(t, c.GetIntPtr("no key"))
+
+	require.True(t, *c.GetBoolPtr("bool"))
+	require.False(t, *c.GetBoolPtr("false"))
+	require.Nil(t, c.GetBoolPtr("invalid"))
+	require.Nil(t, c.GetBoolPtr("no key"))
+
+	c.WithStrictCast(nil)
+	require.Nil(t, c.GetIntPtr("no key"))
+	require.Panics(t, func() {
+		c.GetIntPtr("invalid")
+	})
+}
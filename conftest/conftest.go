@@ -0,0 +1,29 @@
+// Package conftest provides the helpers to use the configuration manager in the Unit Tests.
+package conftest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sv-tools/conf"
+)
+
+// MustLoad creates an instance of Conf with the given readers and loads it
+// The test fails immediately if the loading returns an error.
+func MustLoad(tb testing.TB, readers ...conf.Reader) conf.Conf {
+	tb.Helper()
+
+	c := conf.New().WithReaders(readers...)
+	if err := c.Load(context.Background()); err != nil {
+		tb.Fatalf("failed to load the configuration: %v", err)
+	}
+
+	return c
+}
+
+// WithValues creates an instance of Conf loaded with the given values
+func WithValues(tb testing.TB, values map[string]interface{}) conf.Conf {
+	tb.Helper()
+
+	return MustLoad(tb, conf.NewStaticReader("", values))
+}
@@ -0,0 +1,32 @@
+package conftest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+	"github.com/sv-tools/conf/conftest"
+)
+
+func TestMustLoad(t *testing.T) {
+	t.Parallel()
+
+	c := conftest.MustLoad(t,
+		conf.NewStaticReader("", map[string]interface{}{"foo": 1, "bar": 2}),
+		conf.NewStaticReader("bar", "baz"),
+	)
+	require.Equal(t, 1, c.GetInt("foo"))
+	require.Equal(t, "baz", c.GetString("bar"))
+}
+
+func TestWithValues(t *testing.T) {
+	t.Parallel()
+
+	c := conftest.WithValues(t, map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost",
+		},
+	})
+	require.Equal(t, "localhost", c.GetString("db.host"))
+}
@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Change describes the old and the new values of a changed key
+type Change struct {
+	Old interface{}
+	New interface{}
+}
+
+// Diff describes the difference between two sets of the values
+type Diff struct {
+	// Added contains the new keys and their values
+	Added map[string]interface{}
+	// Removed contains the removed keys and their old values
+	Removed map[string]interface{}
+	// Changed contains the keys with the different values
+	Changed map[string]Change
+}
+
+// Empty returns true if there are no differences
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func diff(oldStorage, newStorage *sync.Map) Diff {
+	d := Diff{
+		Added:   map[string]interface{}{},
+		Removed: map[string]interface{}{},
+		Changed: map[string]Change{},
+	}
+
+	oldStorage.Range(func(key, oldValue interface{}) bool {
+		newValue, ok := newStorage.Load(key)
+		switch {
+		case !ok:
+			d.Removed[key.(string)] = oldValue
+		case !reflect.DeepEqual(oldValue, newValue):
+			d.Changed[key.(string)] = Change{Old: oldValue, New: newValue}
+		}
+		return true
+	})
+
+	newStorage.Range(func(key, newValue interface{}) bool {
+		if _, ok := oldStorage.Load(key); !ok {
+			d.Added[key.(string)] = newValue
+		}
+		return true
+	})
+
+	return d
+}
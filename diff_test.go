@@ -0,0 +1,40 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestConf_LoadDryRun(t *testing.T) {
+	t.Parallel()
+
+	reader := &testReader{data: map[string]interface{}{"foo": 1, "bar": 2}}
+	c := conf.New().WithReaders(reader)
+	require.NoError(t, c.Load(context.Background()))
+
+	d, err := c.LoadDryRun(context.Background())
+	require.NoError(t, err)
+	require.True(t, d.Empty())
+
+	reader.data = map[string]interface{}{"foo": 10, "baz": 3}
+	d, err = c.LoadDryRun(context.Background())
+	require.NoError(t, err)
+	require.False(t, d.Empty())
+	require.Equal(t, map[string]interface{}{"baz": 3}, d.Added)
+	require.Equal(t, map[string]interface{}{"bar": 2}, d.Removed)
+	require.Equal(t, map[string]conf.Change{"foo": {Old: 1, New: 10}}, d.Changed)
+
+	require.Equal(t, 1, c.Get("foo"))
+	require.Equal(t, 2, c.Get("bar"))
+	require.Nil(t, c.Get("baz"))
+	require.ElementsMatch(t, []string{"foo", "bar"}, c.OrderedKeys(""))
+
+	reader.err = errFake
+	_, err = c.LoadDryRun(context.Background())
+	require.ErrorIs(t, err, errFake)
+	require.Equal(t, 1, c.Get("foo"))
+}
@@ -0,0 +1,31 @@
+package conf
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GetEnum casts a value for a given key to a given string type and checks that it is one of the valid values
+// Returns an error if the key is not set or the value is not valid.
+//
+// Example:
+//
+//	type Level string
+//	const (
+//		Debug Level = "debug"
+//		Info  Level = "info"
+//	)
+//	level, err := conf.GetEnum(conf.GlobalConf(), "log.level", Debug, Info)
+func GetEnum[T ~string](c ImmutableConf, key string, valid ...T) (T, error) {
+	value := c.Get(key)
+	if value == nil {
+		return "", fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+
+	v := T(c.GetString(key))
+	if !slices.Contains(valid, v) {
+		return "", fmt.Errorf("%w %q: %q is not one of %q", ErrInvalidValue, key, v, valid)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,36 @@
+package conf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+type testLevel string
+
+const (
+	testLevelDebug testLevel = "debug"
+	testLevelInfo  testLevel = "info"
+)
+
+func TestGetEnum(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("level", "info")
+	c.Set("invalid", "trace")
+
+	level, err := conf.GetEnum(c, "level", testLevelDebug, testLevelInfo)
+	require.NoError(t, err)
+	require.Equal(t, testLevelInfo, level)
+
+	level, err = conf.GetEnum(c, "invalid", testLevelDebug, testLevelInfo)
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.EqualError(t, err, `invalid value "invalid": "trace" is not one of ["debug" "info"]`)
+	require.Empty(t, level)
+
+	_, err = conf.GetEnum(c, "no key", testLevelDebug, testLevelInfo)
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+}
@@ -0,0 +1,54 @@
+package conf
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+type env struct {
+	prefixes []string
+}
+
+func (e *env) Prefix() string {
+	return ""
+}
+
+func (e *env) Read(_ context.Context) (interface{}, error) {
+	environ := os.Environ()
+	res := map[string]interface{}{}
+	for _, prefix := range e.prefixes {
+		for _, kv := range environ {
+			name, value, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(name, prefix) || name == prefix {
+				continue
+			}
+
+			res[envKey(strings.TrimPrefix(name, prefix))] = value
+		}
+	}
+
+	return res, nil
+}
+
+// envKey converts a name of the environment variable to a key, e.g. `DB_HOST` to `db.host`
+func envKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "."))
+}
+
+// NewEnvReader creates an instance of the Reader to read the environment variables with a given prefix
+// The prefix is trimmed, the names are lowercased and the underscores are replaced with dots,
+// so `APP_DB_HOST` with `APP_` prefix is loaded as `db.host` key.
+func NewEnvReader(prefix string) Reader {
+	return NewMultiEnvReader(prefix)
+}
+
+// NewMultiEnvReader creates an instance of the Reader to read the environment variables with the given prefixes
+// The variables are mapped to the keys the same way as by NewEnvReader and the prefixes are read in the given order,
+// so the values of the later prefixes take precedence, e.g. `SERVICE_DB_HOST` overrides `APP_DB_HOST`
+// for the `APP_` and `SERVICE_` prefixes.
+func NewMultiEnvReader(prefixes ...string) Reader {
+	return &env{
+		prefixes: prefixes,
+	}
+}
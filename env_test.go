@@ -0,0 +1,33 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestEnvReader(t *testing.T) {
+	t.Setenv("CONF_TEST_DB_HOST", "localhost")
+	t.Setenv("CONF_TEST_PORT", "5432")
+	t.Setenv("CONF_TEST_", "empty")
+
+	c := conf.New().WithReaders(conf.NewEnvReader("CONF_TEST_"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("port"))
+	require.Nil(t, c.Get(""))
+}
+
+func TestMultiEnvReader(t *testing.T) {
+	t.Setenv("CONF_APP_DB_HOST", "app.example.com")
+	t.Setenv("CONF_APP_DB_PORT", "5432")
+	t.Setenv("CONF_SERVICE_DB_HOST", "service.example.com")
+
+	c := conf.New().WithReaders(conf.NewMultiEnvReader("CONF_APP_", "CONF_SERVICE_"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "service.example.com", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+}
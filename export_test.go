@@ -0,0 +1,12 @@
+package conf
+
+import "io"
+
+// SetStdin replaces the stream used by the NewStdinParser function and returns a function restoring it
+func SetStdin(r io.Reader) (restore func()) {
+	old := stdin
+	stdin = r
+	return func() {
+		stdin = old
+	}
+}
@@ -0,0 +1,106 @@
+package conf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownFormat is an error returned if there is no parser registered for a file extension
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Formats is a global extendable list of the parsers selected by a file extension
+//
+// Example:
+//
+//	conf.Formats[".yaml"] = yamlParser.Parse
+var Formats = map[string]ParseFunc{
+	".json": JSONParseFunc,
+}
+
+type multiFormat struct {
+	files        map[string][]byte
+	noFilePrefix bool
+}
+
+func (m *multiFormat) Prefix() string {
+	return ""
+}
+
+func (m *multiFormat) Read(ctx context.Context) (interface{}, error) {
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var res interface{} = map[string]interface{}{}
+	for _, name := range names {
+		ext := filepath.Ext(name)
+		parse, ok := Formats[ext]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+		}
+
+		data, err := parse(ctx, bytes.NewReader(m.files[name]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if !m.noFilePrefix {
+			data = map[string]interface{}{strings.TrimSuffix(filepath.Base(name), ext): data}
+		}
+		res = merge(res, data)
+	}
+
+	return res, nil
+}
+
+// MultiFormatOption is an option of the Reader created by the NewMultiFormatReader function
+type MultiFormatOption func(m *multiFormat)
+
+// MultiFormatNoFilePrefix makes the multi-format reader merge the data of all files at the root
+// instead of storing the data of each file under the base name of the file.
+func MultiFormatNoFilePrefix() MultiFormatOption {
+	return func(m *multiFormat) {
+		m.noFilePrefix = true
+	}
+}
+
+// NewMultiFormatReader creates an instance of the Reader to parse the given files content
+// The parser of each file is selected from the Formats by the file extension
+// and the data of each file is stored under the base name of the file without extension,
+// so `{"db.json": []byte(`{"host": "localhost"}`)}` is loaded as `db.host` key, see MultiFormatNoFilePrefix.
+// The data of all files is deeply merged in lexical order of the names, so the files with the same base name,
+// e.g. `app.json` and `app.yaml`, are combined and the values of the later file win for the same keys.
+func NewMultiFormatReader(files map[string][]byte, opts ...MultiFormatOption) Reader {
+	m := &multiFormat{
+		files: files,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// NewAutoFileParser creates an instance of the Parser and opens the given file
+// The parser is selected from the Formats by the file extension.
+func NewAutoFileParser(filename string) (Parser, error) {
+	ext := filepath.Ext(filename)
+	parse, ok := Formats[ext]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, filename)
+	}
+
+	p, err := NewFileParser(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.WithParser(parse), nil
+}
@@ -0,0 +1,113 @@
+package conf_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+// testYAMLParseFunc parses the flat YAML mappings of the `key: value` lines
+var testYAMLParseFunc = conf.NewKVParseFunc("\n", ":")
+
+func TestMultiFormatReader(t *testing.T) {
+	conf.Formats[".yaml"] = testYAMLParseFunc
+	t.Cleanup(func() {
+		delete(conf.Formats, ".yaml")
+	})
+
+	c := conf.New().WithReaders(conf.NewMultiFormatReader(map[string][]byte{
+		"config/db.json":  []byte(`{"host": "localhost", "port": 5432}`),
+		"config/app.yaml": []byte("foo: 1\nbar: 2\nname: yaml\n"),
+		"config/app.json": []byte(`{"name": "app", "debug": true}`),
+	}))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+	require.Equal(t, json.Number("5432"), c.Get("db.port"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+	require.Equal(t, 1, c.GetInt("app.foo"))
+	require.Equal(t, 2, c.GetInt("app.bar"))
+	require.Equal(t, "yaml", c.GetString("app.name"))
+	require.True(t, c.GetBool("app.debug"))
+}
+
+func TestMultiFormatReader_WithoutFilePrefix(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewMultiFormatReader(map[string][]byte{
+		"10-base.json":     []byte(`{"db": {"host": "localhost", "port": 5432}, "name": "app"}`),
+		"20-override.json": []byte(`{"db": {"host": "db.example.com"}}`),
+	}, conf.MultiFormatNoFilePrefix()))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+	require.Equal(t, "app", c.GetString("name"))
+	require.Nil(t, c.Get("10-base"))
+}
+
+func TestMultiFormatReader_ErrUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewMultiFormatReader(map[string][]byte{
+		"config.ini": []byte(`foo=1`),
+	}))
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrUnknownFormat)
+}
+
+func TestMultiFormatReader_ParseError(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewMultiFormatReader(map[string][]byte{
+		"config.json": []byte(`{"foo":`),
+	}))
+	require.ErrorContains(t, c.Load(context.Background()), "config.json: unexpected EOF")
+}
+
+func TestAutoFileParser(t *testing.T) {
+	conf.Formats[".txt"] = testParseFunc
+	conf.Formats[".yaml"] = testYAMLParseFunc
+	t.Cleanup(func() {
+		delete(conf.Formats, ".txt")
+		delete(conf.Formats, ".yaml")
+	})
+
+	jsonParser, err := conf.NewAutoFileParser("testdata/data.json")
+	require.NoError(t, err)
+	yamlParser, err := conf.NewAutoFileParser("testdata/data.yaml")
+	require.NoError(t, err)
+	txtParser, err := conf.NewAutoFileParser("testdata/data.txt")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(
+		jsonParser.WithPrefix("json"),
+		yamlParser.WithPrefix("yaml"),
+		txtParser.WithPrefix("txt"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("json.foo"))
+	require.Equal(t, "qux", c.GetString("json.bar.baz"))
+	require.Equal(t, 1, c.GetInt("yaml.foo"))
+	require.Equal(t, 2, c.GetInt("yaml.bar"))
+	require.Equal(t, 1, c.GetInt("txt.foo"))
+	require.Equal(t, 2, c.GetInt("txt.bar"))
+}
+
+func TestAutoFileParser_ErrUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewAutoFileParser("testdata/data.ini")
+	require.ErrorIs(t, err, conf.ErrUnknownFormat)
+	require.Nil(t, parser)
+}
+
+func TestAutoFileParser_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewAutoFileParser("testdata/fake.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, parser)
+}
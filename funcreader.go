@@ -0,0 +1,28 @@
+package conf
+
+import (
+	"context"
+)
+
+type funcReader struct {
+	fetch  func(ctx context.Context) (interface{}, error)
+	prefix string
+}
+
+func (f *funcReader) Prefix() string {
+	return f.prefix
+}
+
+func (f *funcReader) Read(ctx context.Context) (interface{}, error) {
+	return f.fetch(ctx)
+}
+
+// NewFuncReader creates an instance of the Reader calling a given function to fetch the data
+// The function is called on every `Load` call using the given context, so any source,
+// e.g. a custom remote configuration service, can be integrated without defining a new type.
+func NewFuncReader(prefix string, fetch func(ctx context.Context) (interface{}, error)) Reader {
+	return &funcReader{
+		fetch:  fetch,
+		prefix: prefix,
+	}
+}
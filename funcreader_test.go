@@ -0,0 +1,47 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestFuncReader(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+	calls := 0
+	reader := conf.NewFuncReader("remote", func(ctx context.Context) (interface{}, error) {
+		calls++
+		return map[string]interface{}{
+			"region": ctx.Value(ctxKey{}),
+			"calls":  calls,
+		}, nil
+	})
+
+	c := conf.New().WithReaders(reader)
+	ctx := context.WithValue(context.Background(), ctxKey{}, "eu-west-1")
+	require.NoError(t, c.Load(ctx))
+	require.Equal(t, "eu-west-1", c.GetString("remote.region"))
+	require.Equal(t, 1, c.GetInt("remote.calls"))
+
+	require.NoError(t, c.Load(ctx))
+	require.Equal(t, 2, c.GetInt("remote.calls"))
+}
+
+func TestFuncReader_Error(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewFuncReader("", func(context.Context) (interface{}, error) {
+			return map[string]interface{}{"foo": "bar"}, nil
+		}),
+		conf.NewFuncReader("", func(context.Context) (interface{}, error) {
+			return nil, errFake
+		}),
+	)
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+}
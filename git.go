@@ -0,0 +1,123 @@
+package conf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidGitArgument is returned by NewGitReader for an empty repository URL or ref
+// or for the one starting with `-`, which git would treat as an option
+var ErrInvalidGitArgument = errors.New("invalid git argument")
+
+type gitReader struct {
+	mu     sync.Mutex
+	url    string
+	ref    string
+	path   string
+	dir    string
+	parser ParseFunc
+	prefix string
+}
+
+func (g *gitReader) Prefix() string {
+	return g.prefix
+}
+
+func (g *gitReader) Read(ctx context.Context) (interface{}, error) {
+	if g.parser == nil {
+		return nil, ErrNoParser
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, err := g.git(ctx, "fetch", "--quiet", "--depth", "1", "--", "origin", g.ref); err != nil {
+		return nil, err
+	}
+
+	data, err := g.git(ctx, "show", "FETCH_HEAD:"+g.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.parser(ctx, bytes.NewReader(data))
+}
+
+// git runs a git command in the local repository and returns its stdout
+func (g *gitReader) git(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Close removes the local copy of the repository
+func (g *gitReader) Close() error {
+	return os.RemoveAll(g.dir)
+}
+
+func (g *gitReader) Name() string {
+	return g.url + "@" + g.ref + ":" + g.path
+}
+
+func (g *gitReader) WithPrefix(prefix string) Parser {
+	g.prefix = prefix
+	return g
+}
+
+func (g *gitReader) WithParser(parser ParseFunc) Parser {
+	g.parser = parser
+	return g
+}
+
+// NewGitReader creates an instance of the Parser to read a file at a given path of a git repository at a given ref
+// The `git` executable is used to initialize a local repository in a temporary directory,
+// which is kept between the `Load` calls, so only the latest commit of the ref is fetched on every call.
+// The parser is selected from the Formats by the file extension, if registered.
+// The returned parser implements the io.Closer interface to remove the local repository.
+// The repository URL and the ref must not be empty or start with `-`, see ErrInvalidGitArgument.
+func NewGitReader(repoURL, ref, path string) (Parser, error) {
+	for _, arg := range []string{repoURL, ref} {
+		if arg == "" || strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGitArgument, arg)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "conf-git-")
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gitReader{
+		url:    repoURL,
+		ref:    ref,
+		path:   path,
+		dir:    dir,
+		parser: Formats[filepath.Ext(path)],
+	}
+
+	ctx := context.Background()
+	if _, err := g.git(ctx, "init", "--quiet"); err != nil {
+		return nil, errors.Join(err, g.Close())
+	}
+	if _, err := g.git(ctx, "remote", "add", "--", "origin", repoURL); err != nil {
+		return nil, errors.Join(err, g.Close())
+	}
+
+	return g, nil
+}
@@ -0,0 +1,90 @@
+package conf_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func testGitCommit(tb testing.TB, dir, name, content string) {
+	tb.Helper()
+
+	require.NoError(tb, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	for _, args := range [][]string{
+		{"add", name},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "update " + name},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(tb, err, string(out))
+	}
+}
+
+func TestGitReader(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	out, err := exec.Command("git", "init", "--quiet", repo).CombinedOutput()
+	require.NoError(t, err, string(out))
+	testGitCommit(t, repo, "config.json", `{"db": {"host": "localhost"}}`)
+
+	reader, err := conf.NewGitReader("file://"+repo, "HEAD", "config.json")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, reader.(io.Closer).Close()) })
+
+	c := conf.New().WithReaders(reader.WithPrefix("git"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("git.db.host"))
+
+	testGitCommit(t, repo, "config.json", `{"db": {"host": "db.example.com"}}`)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "db.example.com", c.GetString("git.db.host"))
+}
+
+func TestGitReader_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	out, err := exec.Command("git", "init", "--quiet", repo).CombinedOutput()
+	require.NoError(t, err, string(out))
+	testGitCommit(t, repo, "config.json", `{}`)
+
+	reader, err := conf.NewGitReader("file://"+repo, "HEAD", "missing.json")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, reader.(io.Closer).Close()) })
+	require.ErrorContains(t, conf.New().WithReaders(reader).Load(context.Background()), "git show")
+
+	reader, err = conf.NewGitReader("file://"+repo, "no-such-branch", "config.json")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, reader.(io.Closer).Close()) })
+	require.ErrorContains(t, conf.New().WithReaders(reader).Load(context.Background()), "git fetch")
+
+	reader, err = conf.NewGitReader("file://"+repo, "HEAD", "config.txt")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, reader.(io.Closer).Close()) })
+	require.ErrorIs(t, conf.New().WithReaders(reader).Load(context.Background()), conf.ErrNoParser)
+
+	for _, args := range [][2]string{
+		{"--upload-pack=touch /tmp/pwned", "HEAD"},
+		{"file://" + repo, "--upload-pack=touch /tmp/pwned"},
+		{"", "HEAD"},
+		{"file://" + repo, ""},
+	} {
+		_, err = conf.NewGitReader(args[0], args[1], "config.json")
+		require.ErrorIs(t, err, conf.ErrInvalidGitArgument, args)
+	}
+}
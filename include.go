@@ -0,0 +1,151 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrIncludeCycle is an error returned if a file includes itself directly or indirectly
+	ErrIncludeCycle = errors.New("include cycle")
+	// ErrIncludeDepth is an error returned if the included files are nested deeper than MaxIncludeDepth
+	ErrIncludeDepth = errors.New("include depth exceeded")
+)
+
+// MaxIncludeDepth is the maximum nesting level of the files included by the `!include` directive
+const MaxIncludeDepth = 10
+
+const includeDirective = "!include "
+
+type include struct {
+	filename string
+	parser   ParseFunc
+	prefix   string
+}
+
+func (i *include) Prefix() string {
+	return i.prefix
+}
+
+func (i *include) Read(ctx context.Context) (interface{}, error) {
+	if i.parser == nil {
+		return nil, ErrNoParser
+	}
+
+	return i.parse(ctx, i.filename, i.parser, nil)
+}
+
+// parse parses a given file and resolves the include directives of its values
+// The stack contains the absolute paths of the files including the given one.
+func (i *include) parse(ctx context.Context, name string, parse ParseFunc, stack []string) (interface{}, error) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(stack, path) {
+		return nil, fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, path), " -> "))
+	}
+	if len(stack) > MaxIncludeDepth {
+		return nil, fmt.Errorf("%w: %s", ErrIncludeDepth, name)
+	}
+
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	data, err := parse(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return i.resolve(ctx, data, filepath.Dir(path), parse, append(slices.Clip(stack), path))
+}
+
+// resolve replaces the include directives in a given data with the content of the included files
+// The relative paths are resolved against a given directory of the including file.
+func (i *include) resolve(
+	ctx context.Context, data interface{}, dir string, parse ParseFunc, stack []string,
+) (interface{}, error) {
+	switch v := data.(type) {
+	case string:
+		name, ok := strings.CutPrefix(v, includeDirective)
+		if !ok {
+			return v, nil
+		}
+
+		name = strings.TrimSpace(name)
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		if p, ok := Formats[filepath.Ext(name)]; ok {
+			parse = p
+		}
+
+		return i.parse(ctx, name, parse, stack)
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			value, err := i.resolve(ctx, item, dir, parse, stack)
+			if err != nil {
+				return nil, err
+			}
+			res[key] = value
+		}
+
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for idx, item := range v {
+			value, err := i.resolve(ctx, item, dir, parse, stack)
+			if err != nil {
+				return nil, err
+			}
+			res[idx] = value
+		}
+
+		return res, nil
+	}
+
+	return data, nil
+}
+
+func (i *include) WithPrefix(prefix string) Parser {
+	i.prefix = prefix
+	return i
+}
+
+func (i *include) WithParser(parser ParseFunc) Parser {
+	i.parser = parser
+	return i
+}
+
+// NewIncludeFileParser creates an instance of the Parser to read the given file
+// and to splice in the files referenced by the string values in the form of `!include path`.
+// The relative paths are resolved against the directory of the including file and the included files
+// can include other files up to MaxIncludeDepth levels, the include cycles are reported as errors.
+// The parser of each file is selected from the Formats by the file extension, if registered,
+// otherwise the parser of the including file is used.
+//
+// Example of `config.json`:
+//
+//	{
+//	  "name": "app",
+//	  "db": "!include db.json"
+//	}
+func NewIncludeFileParser(filename string) (Parser, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, err
+	}
+
+	return &include{
+		filename: filename,
+		parser:   Formats[filepath.Ext(filename)],
+	}, nil
+}
@@ -0,0 +1,66 @@
+package conf_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestIncludeFileParser(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewIncludeFileParser("testdata/include/config.json")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser.WithPrefix("pr"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "app", c.GetString("pr.name"))
+	require.Equal(t, "localhost", c.GetString("pr.db.host"))
+	require.Equal(t, 5432, c.GetInt("pr.db.port"))
+	require.Equal(t, "auth", c.GetString("pr.plugins.0.name"))
+	require.Equal(t, "localhost", c.GetString("pr.plugins.0.settings.host"))
+	require.Equal(t, "static", c.GetString("pr.plugins.1"))
+}
+
+func TestIncludeFileParser_Cycle(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewIncludeFileParser("testdata/include/cycle.json")
+	require.NoError(t, err)
+	require.ErrorIs(t, conf.New().WithReaders(parser).Load(context.Background()), conf.ErrIncludeCycle)
+}
+
+func TestIncludeFileParser_Depth(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for i := 0; i <= conf.MaxIncludeDepth+1; i++ {
+		data := `{"next": "!include ` + strconv.Itoa(i+1) + `.json"}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(i)+".json"), []byte(data), 0o600))
+	}
+
+	parser, err := conf.NewIncludeFileParser(filepath.Join(dir, "0.json"))
+	require.NoError(t, err)
+	require.ErrorIs(t, conf.New().WithReaders(parser).Load(context.Background()), conf.ErrIncludeDepth)
+}
+
+func TestIncludeFileParser_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := conf.NewIncludeFileParser("testdata/include/fake.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	parser, err := conf.NewIncludeFileParser("testdata/include/db.json")
+	require.NoError(t, err)
+	c := conf.New().WithReaders(parser.WithParser(func(_ context.Context, _ io.Reader) (interface{}, error) {
+		return map[string]interface{}{"missing": "!include fake.json"}, nil
+	}))
+	require.ErrorIs(t, c.Load(context.Background()), os.ErrNotExist)
+}
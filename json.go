@@ -0,0 +1,105 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONOption is an option of the JSON parser created by the NewJSONParseFunc function
+type JSONOption func(decoder *json.Decoder)
+
+// JSONUseNumber makes the JSON parser decode the numbers as `json.Number` instead of float64,
+// so the large integers keep their precision and are cast by the integer getters without rounding.
+func JSONUseNumber() JSONOption {
+	return func(decoder *json.Decoder) {
+		decoder.UseNumber()
+	}
+}
+
+// NewJSONParseFunc creates a ParseFunc to parse a data in JSON format with given options
+// The numbers are decoded as float64 like by `json.Unmarshal` unless JSONUseNumber is given.
+func NewJSONParseFunc(opts ...JSONOption) ParseFunc {
+	return func(_ context.Context, r io.Reader) (interface{}, error) {
+		decoder := json.NewDecoder(r)
+		for _, opt := range opts {
+			opt(decoder)
+		}
+
+		var data interface{}
+		if err := decoder.Decode(&data); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	}
+}
+
+var jsonParseFunc = NewJSONParseFunc(JSONUseNumber())
+
+// JSONParseFunc parses a data in JSON format
+// The numbers are decoded as `json.Number` to keep the precision of the large integers,
+// it is the same as the parser created by `NewJSONParseFunc(JSONUseNumber())`.
+func JSONParseFunc(ctx context.Context, r io.Reader) (interface{}, error) {
+	return jsonParseFunc(ctx, r)
+}
+
+// NDJSONParseFunc parses a data in the newline delimited JSON format
+// Each record becomes an item of the returned slice, so the records are accessed by index, e.g. `0.field`.
+// The blank lines are ignored.
+func NDJSONParseFunc(_ context.Context, r io.Reader) (interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	res := []interface{}{}
+	for {
+		var data interface{}
+		if err := decoder.Decode(&data); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, err
+		}
+		res = append(res, data)
+	}
+}
+
+var errNotJSONArray = errors.New("not a JSON array")
+
+// StreamingJSONArrayParseFunc parses a data in JSON format containing a top level array
+// The items are decoded one by one, so a large array is not buffered as a whole before decoding,
+// and the records are accessed by index, e.g. `0.field`, the same way as by NDJSONParseFunc.
+// The decoding stops if the context is canceled.
+func StreamingJSONArrayParseFunc(ctx context.Context, r io.Reader) (interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("%w: unexpected %v", errNotJSONArray, tok)
+	}
+
+	res := []interface{}{}
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var data interface{}
+		if err := decoder.Decode(&data); err != nil {
+			return nil, err
+		}
+		res = append(res, data)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,150 @@
+package conf_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestJSONParseFunc(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader(`{"foo": 1, "bar": {"baz": 9007199254740993}}`)).
+			WithParser(conf.JSONParseFunc),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("foo"))
+	require.Equal(t, int64(9007199254740993), c.GetInt64("bar.baz"))
+}
+
+func TestNewJSONParseFunc(t *testing.T) {
+	t.Parallel()
+
+	data := `{"id": 9007199254740993}`
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader(data)).WithParser(conf.NewJSONParseFunc()).WithPrefix("float"),
+		conf.NewStreamParser(strings.NewReader(data)).
+			WithParser(conf.NewJSONParseFunc(conf.JSONUseNumber())).
+			WithPrefix("number"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.IsType(t, float64(0), c.Get("float.id"))
+	require.NotEqual(t, int64(9007199254740993), c.GetInt64("float.id"))
+	require.Equal(t, json.Number("9007199254740993"), c.Get("number.id"))
+	require.Equal(t, int64(9007199254740993), c.GetInt64("number.id"))
+
+	_, err := conf.NewJSONParseFunc()(context.Background(), strings.NewReader(`{`))
+	require.Error(t, err)
+}
+
+func TestNDJSONParseFunc(t *testing.T) {
+	t.Parallel()
+
+	data := `{"field": "a", "id": 1}
+
+{"field": "b", "id": 2}
+{"field": "c", "id": 3}
+`
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader(data)).WithParser(conf.NDJSONParseFunc).WithPrefix("events"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Len(t, c.Get("events"), 3)
+	require.Equal(t, "a", c.GetString("events.0.field"))
+	require.Equal(t, "b", c.GetString("events.1.field"))
+	require.Equal(t, "c", c.GetString("events.2.field"))
+	require.Equal(t, 3, c.GetInt("events.2.id"))
+}
+
+func TestNDJSONParseFunc_Error(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader("{\"field\": \"a\"}\n{\"field\":\n")).WithParser(conf.NDJSONParseFunc),
+	)
+	require.Error(t, c.Load(context.Background()))
+}
+
+func testJSONArray(tb testing.TB, n int) string {
+	tb.Helper()
+
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(`{"id": ` + strconv.Itoa(i) + `, "name": "record ` + strconv.Itoa(i) + `", "tags": ["a", "b"]}`)
+	}
+	b.WriteString("]")
+
+	return b.String()
+}
+
+func TestStreamingJSONArrayParseFunc(t *testing.T) {
+	t.Parallel()
+
+	data := testJSONArray(t, 10000)
+	streamed, err := conf.StreamingJSONArrayParseFunc(context.Background(), strings.NewReader(data))
+	require.NoError(t, err)
+	naive, err := conf.JSONParseFunc(context.Background(), strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, naive, streamed)
+
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader(data)).WithParser(conf.StreamingJSONArrayParseFunc).WithPrefix("records"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Len(t, c.Get("records"), 10000)
+	require.Equal(t, 9999, c.GetInt("records.9999.id"))
+	require.Equal(t, "record 42", c.GetString("records.42.name"))
+	require.Equal(t, "b", c.GetString("records.42.tags.1"))
+}
+
+func TestStreamingJSONArrayParseFunc_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := conf.StreamingJSONArrayParseFunc(context.Background(), strings.NewReader(`{"foo": 1}`))
+	require.ErrorContains(t, err, "not a JSON array")
+
+	_, err = conf.StreamingJSONArrayParseFunc(context.Background(), strings.NewReader(`[1, 2`))
+	require.Error(t, err)
+
+	data, err := conf.StreamingJSONArrayParseFunc(context.Background(), strings.NewReader(`[]`))
+	require.NoError(t, err)
+	require.Empty(t, data)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = conf.StreamingJSONArrayParseFunc(ctx, strings.NewReader(`[1, 2]`))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func BenchmarkJSONArray(b *testing.B) {
+	data := testJSONArray(b, 10000)
+
+	b.Run("naive", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := conf.JSONParseFunc(context.Background(), strings.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := conf.StreamingJSONArrayParseFunc(context.Background(), strings.NewReader(data)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
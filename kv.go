@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidPair is an error returned by the key-value parser if a pair has no separator or key
+var ErrInvalidPair = errors.New("invalid key-value pair")
+
+// NewKVParseFunc creates a ParseFunc to parse the key-value pairs separated by given separators
+// The data `foo:1;bar:2` is parsed with `;` and `:` as `{"foo": "1", "bar": "2"}`.
+// The keys and the values are trimmed, the empty pairs, e.g. caused by a trailing separator, are ignored
+// and the value is split by the first key-value separator only, so it may contain the separator.
+// The values are returned as strings, use the getters to cast them.
+func NewKVParseFunc(pairSep, kvSep string) ParseFunc {
+	return func(_ context.Context, r io.Reader) (interface{}, error) {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		res := map[string]interface{}{}
+		for _, pair := range strings.Split(string(data), pairSep) {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+
+			key, value, ok := strings.Cut(pair, kvSep)
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPair, pair)
+			}
+			res[key] = strings.TrimSpace(value)
+		}
+
+		return res, nil
+	}
+}
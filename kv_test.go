@@ -0,0 +1,86 @@
+package conf_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestKVParseFunc(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStreamParser(strings.NewReader(`foo:1;bar:2`)).
+			WithParser(conf.NewKVParseFunc(";", ":")).
+			WithPrefix("pr"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("pr.foo"))
+	require.Equal(t, 2, c.GetInt("pr.bar"))
+}
+
+func TestKVParseFunc_Formats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     string
+		pairSep  string
+		kvSep    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "trailing separators and spaces",
+			data:     " foo : 1 ;; bar:2 ; ",
+			pairSep:  ";",
+			kvSep:    ":",
+			expected: map[string]interface{}{"foo": "1", "bar": "2"},
+		},
+		{
+			name:     "lines",
+			data:     "host=localhost\r\ndsn=postgres://db?sslmode=off\n\nempty=\n",
+			pairSep:  "\n",
+			kvSep:    "=",
+			expected: map[string]interface{}{"host": "localhost", "dsn": "postgres://db?sslmode=off", "empty": ""},
+		},
+		{
+			name:     "multi-character separators",
+			data:     "a => 1, b => 2",
+			pairSep:  ",",
+			kvSep:    "=>",
+			expected: map[string]interface{}{"a": "1", "b": "2"},
+		},
+		{
+			name:     "empty",
+			data:     "",
+			pairSep:  ";",
+			kvSep:    ":",
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := conf.NewKVParseFunc(tt.pairSep, tt.kvSep)(context.Background(), strings.NewReader(tt.data))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, data)
+		})
+	}
+}
+
+func TestKVParseFunc_ErrInvalidPair(t *testing.T) {
+	t.Parallel()
+
+	parse := conf.NewKVParseFunc(";", ":")
+	_, err := parse(context.Background(), strings.NewReader("foo:1;bar"))
+	require.ErrorIs(t, err, conf.ErrInvalidPair)
+	require.EqualError(t, err, `invalid key-value pair: "bar"`)
+
+	_, err = parse(context.Background(), strings.NewReader(":1"))
+	require.ErrorIs(t, err, conf.ErrInvalidPair)
+}
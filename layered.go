@@ -0,0 +1,142 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type layered struct {
+	files  []string
+	dropIn string
+	parser ParseFunc
+	prefix string
+}
+
+func (l *layered) Prefix() string {
+	return l.prefix
+}
+
+func (l *layered) Read(ctx context.Context) (interface{}, error) {
+	if l.parser == nil {
+		return nil, ErrNoParser
+	}
+
+	files, err := l.list()
+	if err != nil {
+		return nil, err
+	}
+
+	var res interface{}
+	for i, name := range files {
+		data, err := l.parse(ctx, name)
+		if err != nil {
+			// only the first file is required, the overlays are optional
+			if i > 0 && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		res = merge(res, data)
+	}
+
+	return res, nil
+}
+
+// list returns the files to be merged including the fragments of the drop-in directory, if any
+// The fragments are listed on every call, so the added or removed fragments are picked up by the next `Load` call.
+func (l *layered) list() ([]string, error) {
+	if l.dropIn == "" {
+		return l.files, nil
+	}
+
+	entries, err := os.ReadDir(l.dropIn)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return l.files, nil
+		}
+		return nil, err
+	}
+
+	files := slices.Clone(l.files)
+	ext := filepath.Ext(l.files[0])
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ext {
+			continue
+		}
+		files = append(files, filepath.Join(l.dropIn, entry.Name()))
+	}
+
+	return files, nil
+}
+
+func (l *layered) parse(ctx context.Context, name string) (interface{}, error) {
+	f, err := os.Open(name) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	data, err := l.parser(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+func (l *layered) WithPrefix(prefix string) Parser {
+	l.prefix = prefix
+	return l
+}
+
+func (l *layered) WithParser(parser ParseFunc) Parser {
+	l.parser = parser
+	return l
+}
+
+// NewLayeredFileParser creates an instance of the Parser to read the given base file
+// and to deeply merge the environment specific file over it, if the file exists.
+// The name of the environment specific file is built by inserting the environment before the extension,
+// so `config.yaml` and `production` give `config.production.yaml`.
+// The parser is selected from the Formats by the file extension, if registered.
+func NewLayeredFileParser(base, env string) (Parser, error) {
+	if _, err := os.Stat(base); err != nil {
+		return nil, err
+	}
+
+	files := []string{base}
+	ext := filepath.Ext(base)
+	if env != "" {
+		files = append(files, strings.TrimSuffix(base, ext)+"."+env+ext)
+	}
+
+	return &layered{
+		files:  files,
+		parser: Formats[ext],
+	}, nil
+}
+
+// NewDropInParser creates an instance of the Parser to read the given main file
+// and to deeply merge the fragments of the drop-in directory over it in lexical order of their names,
+// like the systemd drop-in overrides, so `config.json` is overridden by `config.json.d/10-db.json`
+// and then by `config.json.d/20-cache.json`.
+// Only the fragments with the same extension as the main file are read and the directory is optional.
+// The parser is selected from the Formats by the file extension, if registered.
+func NewDropInParser(mainFile string) (Parser, error) {
+	if _, err := os.Stat(mainFile); err != nil {
+		return nil, err
+	}
+
+	return &layered{
+		files:  []string{mainFile},
+		dropIn: mainFile + ".d",
+		parser: Formats[filepath.Ext(mainFile)],
+	}, nil
+}
@@ -0,0 +1,106 @@
+package conf_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestLayeredFileParser(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewLayeredFileParser("testdata/layered/config.json", "production")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "app", c.GetString("name"))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+}
+
+func TestLayeredFileParser_NoOverlay(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewLayeredFileParser("testdata/layered/config.json", "staging")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser.WithPrefix("pr"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "app", c.GetString("pr.name"))
+	require.Equal(t, "localhost", c.GetString("pr.db.host"))
+	require.Equal(t, 5432, c.GetInt("pr.db.port"))
+}
+
+func TestLayeredFileParser_ErrNoParser(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewLayeredFileParser("testdata/data.txt", "production")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser)
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrNoParser)
+
+	c = conf.New().WithReaders(parser.WithParser(testParseFunc))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("foo"))
+}
+
+func TestLayeredFileParser_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewLayeredFileParser("testdata/layered/fake.json", "production")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, parser)
+}
+
+func TestDropInParser(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewDropInParser("testdata/dropin/config.json")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "app", c.GetString("name"))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+	require.Equal(t, 5432, c.GetInt("db.port"))
+	require.Equal(t, "admin", c.GetString("db.user"))
+	require.Equal(t, "5m", c.GetString("cache.ttl"))
+}
+
+func TestDropInParser_NewFragment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	main := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(main, []byte(`{"port": 80}`), 0o600))
+
+	parser, err := conf.NewDropInParser(main)
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(parser.WithPrefix("pr"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 80, c.GetInt("pr.port"))
+
+	require.NoError(t, os.Mkdir(main+".d", 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(main+".d", "override.json"), []byte(`{"port": 8080}`), 0o600))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 8080, c.GetInt("pr.port"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(main+".d", "broken.json"), []byte(`{`), 0o600))
+	require.Error(t, c.Load(context.Background()))
+}
+
+func TestDropInParser_NotFound(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewDropInParser("testdata/dropin/fake.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, parser)
+}
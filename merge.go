@@ -0,0 +1,49 @@
+package conf
+
+import (
+	"reflect"
+
+	"github.com/spf13/cast"
+)
+
+// merge deeply merges the src data into the dst data and returns the result
+// The maps are merged recursively and any other values of src replace the values of dst.
+// The given data is not modified.
+func merge(dst, src interface{}) interface{} {
+	dm, ok := toStringMap(dst)
+	if !ok {
+		return src
+	}
+	sm, ok := toStringMap(src)
+	if !ok {
+		return src
+	}
+
+	res := make(map[string]interface{}, len(dm)+len(sm))
+	for k, v := range dm {
+		res[k] = v
+	}
+	for k, v := range sm {
+		if old, ok := res[k]; ok {
+			v = merge(old, v)
+		}
+		res[k] = v
+	}
+
+	return res
+}
+
+func toStringMap(data interface{}) (map[string]interface{}, bool) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	res := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		res[cast.ToString(iter.Key().Interface())] = iter.Value().Interface()
+	}
+
+	return res, true
+}
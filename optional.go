@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+// errSkipped is returned by the optional reader for a missing source, so the Load function stores no data,
+// not even the prefix of the reader
+var errSkipped = errors.New("skipped")
+
+type optional struct {
+	reader   Reader
+	notFound []func(err error) bool
+}
+
+func (o *optional) Prefix() string {
+	return o.reader.Prefix()
+}
+
+func (o *optional) Read(ctx context.Context) (interface{}, error) {
+	data, err := o.reader.Read(ctx)
+	if err != nil && o.isNotFound(err) {
+		return nil, errSkipped
+	}
+
+	return data, err
+}
+
+func (o *optional) isNotFound(err error) bool {
+	if errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+
+	for _, fn := range o.notFound {
+		if fn(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *optional) Unwrap() Reader {
+	return o.reader
+}
+
+// Optional wraps a given reader to ignore the errors of the missing sources
+// An error is treated as "not found" if it wraps `fs.ErrNotExist` or any of the given functions returns true,
+// in this case no data is stored, not even the prefix of the reader, so the defaults of the prefix are kept.
+// All other errors are returned as is.
+// The file parsers open the file in their constructors, use NewLazyFileParser to make a missing file optional.
+//
+// Example:
+//
+//	conf.WithReaders(conf.Optional(conf.NewLazyFileParser("config.local.json")))
+func Optional(reader Reader, notFound ...func(err error) bool) Reader {
+	return &optional{
+		reader:   reader,
+		notFound: notFound,
+	}
+}
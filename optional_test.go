@@ -0,0 +1,85 @@
+package conf_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStaticReader("", map[string]interface{}{"foo": 1}),
+		conf.Optional(conf.NewLazyFileParser("testdata/config.local.json").WithPrefix("db")),
+	)
+	c.SetDefault("db", "default")
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("foo"))
+	require.Equal(t, "default", c.Get("db"))
+	require.Equal(t, []string{"foo"}, c.OrderedKeys(""))
+
+	c = conf.New().WithReaders(
+		conf.Optional(conf.NewLazyFileParser("testdata/config.local.json").WithPrefix("db")),
+	).WithRequiredKeys("db")
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrMissingKeys)
+
+	c = conf.New().WithReaders(conf.NewLazyFileParser("testdata/config.local.json"))
+	require.ErrorIs(t, c.Load(context.Background()), os.ErrNotExist)
+}
+
+func TestOptional_Reload(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "config.local.json")
+	c := conf.New().WithReaders(conf.Optional(conf.NewLazyFileParser(filename).WithPrefix("db")))
+	require.NoError(t, c.Load(context.Background()))
+	require.Nil(t, c.Get("db"))
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"host": "localhost"}`), 0o600))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"host": "db.example.com"}`), 0o600))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "db.example.com", c.GetString("db.host"))
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"host":`), 0o600))
+	require.Error(t, c.Load(context.Background()))
+}
+
+func TestOptional_Found(t *testing.T) {
+	t.Parallel()
+
+	parser, err := conf.NewLayeredFileParser("testdata/layered/config.json", "")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(conf.Optional(parser.WithPrefix("pr")))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "app", c.GetString("pr.name"))
+}
+
+func TestOptional_Predicate(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("not found")
+	c := conf.New().WithReaders(
+		conf.Optional(conf.NewErrorReader(errNotFound), func(err error) bool {
+			return errors.Is(err, errNotFound)
+		}),
+	)
+	require.NoError(t, c.Load(context.Background()))
+}
+
+func TestOptional_Error(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.Optional(conf.NewErrorReader(errFake)))
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+}
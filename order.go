@@ -0,0 +1,102 @@
+package conf
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// OrderedMap is a map preserving the order of the items
+// Can be returned by the readers to keep the source order of the keys, see OrderedKeys.
+type OrderedMap []KeyValue
+
+// KeyValue is an item of the OrderedMap
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// keyOrder is an index of the stored keys in order of the first storing and of their latest sources
+type keyOrder struct {
+	mu      sync.Mutex
+	keys    []string
+	index   map[string]struct{}
+	sources map[string]string
+}
+
+func (o *keyOrder) add(key, source string) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.sources == nil {
+		o.sources = map[string]string{}
+	}
+	o.sources[key] = source
+
+	if _, ok := o.index[key]; ok {
+		return
+	}
+	if o.index == nil {
+		o.index = map[string]struct{}{}
+	}
+	o.index[key] = struct{}{}
+	o.keys = append(o.keys, key)
+}
+
+func (o *keyOrder) delete(keys ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, key := range keys {
+		delete(o.index, key)
+		delete(o.sources, key)
+	}
+	o.keys = slices.DeleteFunc(o.keys, func(key string) bool {
+		_, ok := o.index[key]
+		return !ok
+	})
+}
+
+func (o *keyOrder) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.keys = nil
+	o.index = nil
+	o.sources = nil
+}
+
+// replace replaces the keys and the sources by the ones of a given order
+func (o *keyOrder) replace(other *keyOrder) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.keys = other.keys
+	o.index = other.index
+	o.sources = other.sources
+}
+
+func (o *keyOrder) source(key string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.sources[key]
+}
+
+func (o *keyOrder) list(prefix string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var keys []string
+	for _, key := range o.keys {
+		if prefix == "" || strings.HasPrefix(key, prefix+".") {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
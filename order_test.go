@@ -0,0 +1,52 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestConf_OrderedKeys(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(conf.NewStaticReader("", conf.OrderedMap{
+		{Key: "name", Value: "app"},
+		{Key: "middlewares", Value: conf.OrderedMap{
+			{Key: "recovery", Value: true},
+			{Key: "logging", Value: conf.OrderedMap{{Key: "level", Value: "info"}}},
+			{Key: "auth", Value: false},
+		}},
+	}))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, []string{
+		"name",
+		"middlewares",
+		"middlewares.recovery",
+		"middlewares.logging",
+		"middlewares.logging.level",
+		"middlewares.auth",
+	}, c.OrderedKeys(""))
+	require.Equal(t, []string{
+		"middlewares.recovery",
+		"middlewares.logging",
+		"middlewares.logging.level",
+		"middlewares.auth",
+	}, c.OrderedKeys("middlewares"))
+	require.Equal(t, "info", c.Get("middlewares.logging.level"))
+
+	c.Set("middlewares.cors", true)
+	c.Set("middlewares.recovery", false)
+	require.Equal(t, []string{
+		"middlewares.recovery",
+		"middlewares.logging",
+		"middlewares.logging.level",
+		"middlewares.auth",
+		"middlewares.cors",
+	}, c.OrderedKeys("middlewares"))
+
+	c.Reset()
+	require.Empty(t, c.OrderedKeys(""))
+}
@@ -0,0 +1,70 @@
+package conf
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Overlay is a thread-safe in-memory set of the values read by the overlay reader
+// The values are changed at runtime, e.g. by an admin endpoint, and survive reloading,
+// because the reader returns them on every `Load` call.
+// The zero value is an empty overlay, use NewOverlayReader to get the reader of the overlay.
+type Overlay struct {
+	mu   sync.RWMutex
+	data map[string]interface{}
+}
+
+// SetKey sets a value of a given key
+// The changes are applied by the next `Load` call.
+func (o *Overlay) SetKey(key string, value interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.data == nil {
+		o.data = map[string]interface{}{}
+	}
+	o.data[key] = value
+}
+
+// DeleteKey deletes a given key
+// The changes are applied by the next `Load` call.
+func (o *Overlay) DeleteKey(key string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.data, key)
+}
+
+type overlayReader struct {
+	overlay *Overlay
+}
+
+func (r *overlayReader) Prefix() string {
+	return ""
+}
+
+func (r *overlayReader) Read(_ context.Context) (interface{}, error) {
+	r.overlay.mu.RLock()
+	defer r.overlay.mu.RUnlock()
+
+	return maps.Clone(r.overlay.data), nil
+}
+
+// NewOverlayReader creates an instance of the Overlay and the Reader returning its current values
+// The keys are stored as is, so `features.beta` overrides the same key of the other readers,
+// but the parent key `features` is not changed. Add the reader last to take precedence over the other readers.
+//
+// Example:
+//
+//	overlay, reader := conf.NewOverlayReader()
+//	conf.WithReaders(fileReader, reader)
+//	overlay.SetKey("features.beta", true)
+//	conf.Load(ctx)
+func NewOverlayReader() (*Overlay, Reader) {
+	overlay := &Overlay{
+		data: map[string]interface{}{},
+	}
+
+	return overlay, &overlayReader{overlay: overlay}
+}
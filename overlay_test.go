@@ -0,0 +1,81 @@
+package conf_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestOverlayReader(t *testing.T) {
+	t.Parallel()
+
+	overlay, reader := conf.NewOverlayReader()
+	c := conf.New().WithReaders(
+		conf.NewStaticReader("features", map[string]interface{}{"beta": false, "dark": true}),
+		reader,
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.False(t, c.GetBool("features.beta"))
+
+	overlay.SetKey("features.beta", true)
+	overlay.SetKey("features.new", "on")
+	require.False(t, c.GetBool("features.beta"))
+	require.NoError(t, c.Load(context.Background()))
+	require.True(t, c.GetBool("features.beta"))
+	require.True(t, c.GetBool("features.dark"))
+	require.Equal(t, "on", c.GetString("features.new"))
+
+	require.NoError(t, c.Load(context.Background()))
+	require.True(t, c.GetBool("features.beta"))
+
+	overlay.DeleteKey("features.beta")
+	overlay.DeleteKey("features.new")
+	require.NoError(t, c.Load(context.Background()))
+	require.False(t, c.GetBool("features.beta"))
+	require.Nil(t, c.Get("features.new"))
+}
+
+func TestOverlayReader_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	overlay, reader := conf.NewOverlayReader()
+	c := conf.New().WithReaders(reader)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "key" + strconv.Itoa(i)
+			overlay.SetKey(key, i)
+			overlay.DeleteKey(key)
+			overlay.SetKey(key, i)
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			_ = c.Load(context.Background())
+		}
+	}()
+	wg.Wait()
+
+	require.NoError(t, c.Load(context.Background()))
+	require.Len(t, c.Keys(), 10)
+	require.Equal(t, 7, c.GetInt("key7"))
+}
+
+func TestOverlay_ZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var overlay conf.Overlay
+	overlay.DeleteKey("foo")
+	require.NotPanics(t, func() { overlay.SetKey("foo", 1) })
+	overlay.DeleteKey("foo")
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
 )
 
 // ParseFunc is a type for the parsing function
@@ -81,3 +82,65 @@ func NewFileParser(filename string) (Parser, error) {
 
 	return NewStreamParser(f), nil
 }
+
+type fileParser struct {
+	filename string
+	parser   ParseFunc
+	prefix   string
+}
+
+func (p *fileParser) Prefix() string {
+	return p.prefix
+}
+
+func (p *fileParser) Read(ctx context.Context) (interface{}, error) {
+	if p.parser == nil {
+		return nil, ErrNoParser
+	}
+
+	f, err := os.Open(p.filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.parser(ctx, f)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	return data, f.Close()
+}
+
+func (p *fileParser) Name() string {
+	return p.filename
+}
+
+func (p *fileParser) WithPrefix(prefix string) Parser {
+	p.prefix = prefix
+	return p
+}
+
+func (p *fileParser) WithParser(parser ParseFunc) Parser {
+	p.parser = parser
+	return p
+}
+
+// NewLazyFileParser creates an instance of the Parser to read a given file
+// Unlike NewFileParser, the file is opened by every call of the `Read` function, so the file is read again
+// on every `Load` and a missing file is reported by the `Load` function, see Optional to ignore it.
+// The parser is selected from the Formats by the file extension, if registered.
+func NewLazyFileParser(filename string) Parser {
+	return &fileParser{
+		filename: filename,
+		parser:   Formats[filepath.Ext(filename)],
+	}
+}
+
+// stdin is a stream used by the NewStdinParser function, replaced by the tests
+var stdin io.Reader = os.Stdin
+
+// NewStdinParser creates an instance of the Parser to read from the standard input
+// The standard input is not closed after reading.
+func NewStdinParser() Parser {
+	return NewStreamParser(struct{ io.Reader }{stdin})
+}
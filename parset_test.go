@@ -82,3 +82,27 @@ func TestFileParser_FileNotFound(t *testing.T) {
 	require.EqualError(t, err, "open testdata/fake.txt: no such file or directory")
 	require.Nil(t, parser)
 }
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStdinParser(t *testing.T) {
+	stream := &closeTracker{Reader: strings.NewReader(`foo:1;bar:2`)}
+	restore := conf.SetStdin(stream)
+	t.Cleanup(restore)
+	parser := conf.NewStdinParser()
+	restore()
+
+	c := conf.New().WithReaders(parser.WithParser(testParseFunc).WithPrefix("pr"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("pr.foo"))
+	require.Equal(t, 2, c.GetInt("pr.bar"))
+	require.False(t, stream.closed)
+}
@@ -2,6 +2,7 @@ package conf
 
 import (
 	"context"
+	"fmt"
 )
 
 // Reader is an interface for the configuration readers
@@ -11,3 +12,74 @@ type Reader interface {
 	// Prefix returns a prefix to be used for all keys of the values provided by the reader
 	Prefix() string
 }
+
+// HealthChecker is an optional interface for the readers that can check the availability of their source
+type HealthChecker interface {
+	// HealthCheck performs a lightweight check of the source, e.g. a ping of a remote backend
+	HealthCheck(ctx context.Context) error
+}
+
+// Named is an optional interface for the readers to identify their source by a human-readable name
+// The name is used in the errors returned by the Load function instead of the type of the reader.
+type Named interface {
+	// Name returns the name of the source, e.g. a path to a file
+	Name() string
+}
+
+// LoadError is an error returned by the Load function if a reader fails
+type LoadError struct {
+	// Index is the position of the reader in the list of the readers
+	Index int
+	// Name is the name of the reader, if it implements the Named interface, or its type
+	Name string
+	// Reader is the failed reader
+	Reader Reader
+	// Err is the error returned by the reader
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("reader %s: %s", e.Name, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// readerName returns the name of a given reader, if it implements the Named interface, or its type
+func readerName(reader Reader) string {
+	if n, ok := as[Named](reader); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", reader)
+}
+
+// readerSource returns the source of the values of a given reader at a given position
+func readerSource(index int, reader Reader) string {
+	return fmt.Sprintf("reader[%d] %s", index, readerName(reader))
+}
+
+// AsHealthChecker returns the given reader as the HealthChecker, if it implements the interface
+// The readers wrapping another reader are unwrapped using the `Unwrap() Reader` method.
+func AsHealthChecker(reader Reader) (HealthChecker, bool) {
+	return as[HealthChecker](reader)
+}
+
+// as walks the chain of the wrapped readers and returns the first one implementing the given interface
+func as[T any](reader Reader) (T, bool) {
+	for reader != nil {
+		if v, ok := reader.(T); ok {
+			return v, true
+		}
+
+		u, ok := reader.(interface{ Unwrap() Reader })
+		if !ok {
+			break
+		}
+		reader = u.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,103 @@
+package conf_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+type testWrapReader struct {
+	testReader
+	reader conf.Reader
+}
+
+func (t *testWrapReader) Unwrap() conf.Reader {
+	return t.reader
+}
+
+func TestAsHealthChecker(t *testing.T) {
+	t.Parallel()
+
+	healthy := &testHealthCheckReader{}
+
+	checker, ok := conf.AsHealthChecker(healthy)
+	require.True(t, ok)
+	require.Equal(t, healthy, checker)
+
+	checker, ok = conf.AsHealthChecker(&testWrapReader{reader: &testWrapReader{reader: healthy}})
+	require.True(t, ok)
+	require.Equal(t, healthy, checker)
+
+	checker, ok = conf.AsHealthChecker(newReader(t, "", nil, nil))
+	require.False(t, ok)
+	require.Nil(t, checker)
+
+	checker, ok = conf.AsHealthChecker(&testWrapReader{reader: newReader(t, "", nil, nil)})
+	require.False(t, ok)
+	require.Nil(t, checker)
+
+	checker, ok = conf.AsHealthChecker(nil)
+	require.False(t, ok)
+	require.Nil(t, checker)
+}
+
+type testNamedReader struct {
+	conf.Reader
+	name string
+}
+
+func (r *testNamedReader) Name() string {
+	return r.name
+}
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+
+	named := &testNamedReader{Reader: newReader(t, "", nil, errFake), name: "vault secrets"}
+	err := conf.New().WithReaders(newReader(t, "", nil, nil), named).Load(context.Background())
+	require.ErrorIs(t, err, errFake)
+	require.EqualError(t, err, "reader vault secrets: fake error")
+
+	err = conf.New().WithReaders(conf.Optional(named, func(error) bool { return false })).Load(context.Background())
+	require.EqualError(t, err, "reader vault secrets: fake error")
+
+	err = conf.New().WithReaders(newReader(t, "", nil, errFake)).Load(context.Background())
+	require.EqualError(t, err, "reader *conf_test.testReader: fake error")
+}
+
+var errOther = errors.New("other error")
+
+func TestLoadError(t *testing.T) {
+	t.Parallel()
+
+	failed := newReader(t, "", nil, errFake)
+	named := &testNamedReader{Reader: newReader(t, "", nil, errOther), name: "vault secrets"}
+	readers := []conf.Reader{newReader(t, "", map[string]interface{}{"foo": "bar"}, nil), failed, named}
+
+	err := conf.New().WithReaders(readers...).Load(context.Background())
+	var loadErr *conf.LoadError
+	require.ErrorAs(t, err, &loadErr)
+	require.Equal(t, 1, loadErr.Index)
+	require.Equal(t, "*conf_test.testReader", loadErr.Name)
+	require.Same(t, failed, loadErr.Reader)
+	require.ErrorIs(t, err, errFake)
+	require.NotErrorIs(t, err, errOther)
+
+	c := conf.New().WithReaders(readers...).WithCollectLoadErrors()
+	err = c.Load(context.Background())
+	require.ErrorIs(t, err, errFake)
+	require.ErrorIs(t, err, errOther)
+	require.EqualError(t, err, "reader *conf_test.testReader: fake error\nreader vault secrets: other error")
+	require.Equal(t, "bar", c.GetString("foo"))
+
+	var errs interface{ Unwrap() []error }
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Unwrap(), 2)
+	require.ErrorAs(t, errs.Unwrap()[1], &loadErr)
+	require.Equal(t, 2, loadErr.Index)
+	require.Equal(t, "vault secrets", loadErr.Name)
+}
@@ -0,0 +1,122 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cast"
+)
+
+var (
+	errNegativeSize = errors.New("negative size")
+	errUnknownUnit  = errors.New("unknown size unit")
+	errNoSuchUnit   = errors.New("unknown unit")
+)
+
+// byteUnits is a list of the supported units of the sizes with their multipliers
+// The units are matched case-insensitively.
+var byteUnits = []struct {
+	name       string
+	multiplier uint64
+}{
+	{"B", 1},
+	{"KB", 1e3},
+	{"MB", 1e6},
+	{"GB", 1e9},
+	{"TB", 1e12},
+	{"PB", 1e15},
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"TiB", 1 << 40},
+	{"PiB", 1 << 50},
+}
+
+// parseByteSize converts a given value to the number of bytes and returns the unit of the value
+// The strings are parsed in the form of `1.5GB` or `512 KiB`, the numbers are the bytes.
+func parseByteSize(value interface{}) (uint64, string, error) {
+	s, ok := value.(string)
+	if !ok {
+		n, err := cast.ToInt64E(value)
+		if err != nil {
+			return 0, "", err
+		}
+		if n < 0 {
+			return 0, "", fmt.Errorf("%w: %d", errNegativeSize, n)
+		}
+
+		return uint64(n), "B", nil //nolint:gosec // the range is checked
+	}
+
+	number, unit := strings.TrimSpace(s), "B"
+	if i := strings.IndexFunc(number, unicode.IsLetter); i >= 0 {
+		number, unit = strings.TrimSpace(number[:i]), number[i:]
+	}
+
+	var multiplier uint64
+	for _, u := range byteUnits {
+		if strings.EqualFold(u.name, unit) {
+			multiplier, unit = u.multiplier, u.name
+			break
+		}
+	}
+	if multiplier == 0 {
+		return 0, "", fmt.Errorf("%w: %q", errUnknownUnit, unit)
+	}
+
+	if strings.HasPrefix(number, "-") {
+		return 0, "", fmt.Errorf("%w: %q", errNegativeSize, s)
+	}
+
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > math.MaxUint64/multiplier {
+			return 0, "", fmt.Errorf("%w: %q does not fit uint64", ErrOverflow, s)
+		}
+
+		return n * multiplier, unit, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, "", err
+	}
+
+	f *= float64(multiplier)
+	if f >= math.MaxUint64 {
+		return 0, "", fmt.Errorf("%w: %q does not fit uint64", ErrOverflow, s)
+	}
+
+	return uint64(f), unit, nil //nolint:gosec // the range is checked
+}
+
+// parseNumberWithUnit converts a given value to a number multiplied by the multiplier of its unit
+// The strings are parsed in the form of `5m` or `1.5 kb`, the unit is the text after the last digit
+// and is matched case-sensitively. The numbers and the strings without a unit use the empty unit,
+// if present in a given table, or the multiplier 1.
+func parseNumberWithUnit(value interface{}, units map[string]float64) (float64, error) {
+	number, unit := value, ""
+	if s, ok := value.(string); ok {
+		s = strings.TrimSpace(s)
+		i := strings.LastIndexFunc(s, func(r rune) bool { return unicode.IsDigit(r) || r == '.' }) + 1
+		number, unit = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+	}
+
+	multiplier, ok := units[unit]
+	if !ok {
+		if unit != "" {
+			return 0, fmt.Errorf("%w: %q", errNoSuchUnit, unit)
+		}
+		multiplier = 1
+	}
+
+	f, err := cast.ToFloat64E(number)
+	if err != nil {
+		return 0, err
+	}
+
+	return f * multiplier, nil
+}
@@ -0,0 +1,118 @@
+package conf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestConf_GetByteSize(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New()
+	c.Set("decimal", "1.5GB")
+	c.Set("binary", "512 kib")
+	c.Set("plain", "100")
+	c.Set("number", 42)
+	c.Set("zero", "0MB")
+	c.Set("negative", "-1KB")
+	c.Set("negative number", -1)
+	c.Set("unknown", "1XB")
+	c.Set("invalid", "abc")
+	c.Set("overflow", "20EB")
+	c.Set("huge", "20000PiB")
+
+	value, unit, err := c.GetByteSize("decimal")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1_500_000_000), value)
+	require.Equal(t, "GB", unit)
+
+	value, unit, err = c.GetByteSize("binary")
+	require.NoError(t, err)
+	require.Equal(t, uint64(512*1024), value)
+	require.Equal(t, "KiB", unit)
+
+	value, unit, err = c.GetByteSize("plain")
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), value)
+	require.Equal(t, "B", unit)
+
+	value, unit, err = c.GetByteSize("number")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), value)
+	require.Equal(t, "B", unit)
+
+	value, unit, err = c.GetByteSize("zero")
+	require.NoError(t, err)
+	require.Zero(t, value)
+	require.Equal(t, "MB", unit)
+
+	_, _, err = c.GetByteSize("negative")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.ErrorContains(t, err, "negative size")
+
+	_, _, err = c.GetByteSize("negative number")
+	require.ErrorContains(t, err, "negative size")
+
+	_, _, err = c.GetByteSize("unknown")
+	require.ErrorContains(t, err, "unknown size unit")
+
+	_, _, err = c.GetByteSize("invalid")
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+
+	_, _, err = c.GetByteSize("overflow")
+	require.ErrorContains(t, err, "unknown size unit")
+
+	_, _, err = c.GetByteSize("huge")
+	require.ErrorIs(t, err, conf.ErrOverflow)
+
+	_, _, err = c.GetByteSize("no key")
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+}
+
+func TestConf_GetNumberWithUnit(t *testing.T) {
+	t.Parallel()
+
+	units := map[string]float64{"s": 1, "m": 60, "h": 3600, "kb": 1000, "M": 1e6}
+	c := conf.New()
+	c.Set("minutes", "5m")
+	c.Set("fraction", "1.5 h")
+	c.Set("size", "10kb")
+	c.Set("mega", "2M")
+	c.Set("negative", "-30s")
+	c.Set("plain", "42")
+	c.Set("number", 7)
+	c.Set("unknown", "3d")
+	c.Set("case", "1KB")
+	c.Set("invalid", "abc")
+
+	for key, expected := range map[string]float64{
+		"minutes":  300,
+		"fraction": 5400,
+		"size":     10_000,
+		"mega":     2e6,
+		"negative": -30,
+		"plain":    42,
+		"number":   7,
+	} {
+		value, err := c.GetNumberWithUnit(key, units)
+		require.NoError(t, err, key)
+		require.InDelta(t, expected, value, 1e-9, key)
+	}
+
+	value, err := c.GetNumberWithUnit("plain", map[string]float64{"": 1024})
+	require.NoError(t, err)
+	require.InDelta(t, 42*1024, value, 1e-9)
+
+	for _, key := range []string{"unknown", "case", "invalid"} {
+		_, err = c.GetNumberWithUnit(key, units)
+		require.ErrorIs(t, err, conf.ErrInvalidValue, key)
+	}
+	_, err = c.GetNumberWithUnit("unknown", units)
+	require.EqualError(t, err, `invalid value "unknown": unknown unit: "d"`)
+
+	_, err = c.GetNumberWithUnit("no key", units)
+	require.ErrorIs(t, err, conf.ErrKeyNotFound)
+}
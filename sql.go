@@ -0,0 +1,92 @@
+package conf
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cast"
+)
+
+// ErrColumnNotFound is an error returned if the result of a query has no column with a given name
+var ErrColumnNotFound = errors.New("column not found")
+
+type sqlReader struct {
+	db     *sql.DB
+	query  string
+	keyCol string
+	valCol string
+}
+
+func (s *sqlReader) Prefix() string {
+	return ""
+}
+
+func (s *sqlReader) Read(ctx context.Context) (interface{}, error) {
+	rows, err := s.db.QueryContext(ctx, s.query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	keyIdx := slices.Index(columns, s.keyCol)
+	if keyIdx < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, s.keyCol)
+	}
+	valIdx := slices.Index(columns, s.valCol)
+	if valIdx < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, s.valCol)
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	res := map[string]interface{}{}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		key, err := cast.ToStringE(sqlValue(values[keyIdx]))
+		if err != nil {
+			return nil, err
+		}
+		res[key] = sqlValue(values[valIdx])
+	}
+
+	return res, rows.Err()
+}
+
+// sqlValue converts the raw bytes returned by the drivers for the text columns to a string
+// The values of all other types are returned as is.
+func sqlValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+
+	return value
+}
+
+// NewSQLReader creates an instance of the Reader to load the key-value pairs returned by a given query
+// The query is executed on every `Load` call using the given context and the values of the key and value columns
+// of each row are stored, so `SELECT name, value FROM settings` with `name` and `value` columns
+// stores a row `("db.host", "localhost")` as `db.host` key.
+// The types of the values returned by the driver are preserved, except the raw bytes converted to strings.
+func NewSQLReader(db *sql.DB, query, keyCol, valCol string) Reader {
+	return &sqlReader{
+		db:     db,
+		query:  query,
+		keyCol: keyCol,
+		valCol: valCol,
+	}
+}
@@ -0,0 +1,133 @@
+package conf_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+// testSQLDriver returns the same rows for any query, the connection name is the key of the rows
+type testSQLDriver struct{}
+
+var testSQLRows = map[string][][]driver.Value{
+	"settings": {
+		{[]byte("db.host"), []byte("localhost")},
+		{[]byte("db.port"), int64(5432)},
+		{[]byte("debug"), true},
+		{[]byte("ratio"), 0.5},
+		{[]byte("empty"), nil},
+	},
+}
+
+func init() {
+	sql.Register("conf-test", testSQLDriver{})
+}
+
+func (testSQLDriver) Open(name string) (driver.Conn, error) {
+	return &testSQLConn{rows: testSQLRows[name]}, nil
+}
+
+type testSQLConn struct {
+	rows [][]driver.Value
+}
+
+func (c *testSQLConn) Prepare(string) (driver.Stmt, error) {
+	return &testSQLStmt{rows: c.rows}, nil
+}
+
+func (c *testSQLConn) Close() error {
+	return nil
+}
+
+func (c *testSQLConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type testSQLStmt struct {
+	rows [][]driver.Value
+}
+
+func (s *testSQLStmt) Close() error {
+	return nil
+}
+
+func (s *testSQLStmt) NumInput() int {
+	return 0
+}
+
+func (s *testSQLStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *testSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &testSQLRowsIter{rows: s.rows}, nil
+}
+
+type testSQLRowsIter struct {
+	rows [][]driver.Value
+}
+
+func (r *testSQLRowsIter) Columns() []string {
+	return []string{"name", "value"}
+}
+
+func (r *testSQLRowsIter) Close() error {
+	return nil
+}
+
+func (r *testSQLRowsIter) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLReader(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("conf-test", "settings")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	c := conf.New().WithReaders(conf.NewSQLReader(db, "SELECT name, value FROM settings", "name", "value"))
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.Get("db.host"))
+	require.Equal(t, int64(5432), c.Get("db.port"))
+	require.Equal(t, true, c.Get("debug"))
+	require.InDelta(t, 0.5, c.Get("ratio"), 0)
+	require.Contains(t, c.Keys(), "empty")
+	require.Nil(t, c.Get("empty"))
+}
+
+func TestSQLReader_ErrColumnNotFound(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("conf-test", "settings")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	c := conf.New().WithReaders(conf.NewSQLReader(db, "SELECT name, value FROM settings", "key", "value"))
+	require.ErrorIs(t, c.Load(context.Background()), conf.ErrColumnNotFound)
+}
+
+func TestSQLReader_Context(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("conf-test", "settings")
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := conf.New().WithReaders(conf.NewSQLReader(db, "SELECT name, value FROM settings", "name", "value"))
+	require.ErrorIs(t, c.Load(ctx), context.Canceled)
+}
@@ -0,0 +1,34 @@
+package conf
+
+import (
+	"context"
+)
+
+type static struct {
+	data   interface{}
+	err    error
+	prefix string
+}
+
+func (s *static) Prefix() string {
+	return s.prefix
+}
+
+func (s *static) Read(_ context.Context) (interface{}, error) {
+	return s.data, s.err
+}
+
+// NewStaticReader creates an instance of the Reader returning the given data
+func NewStaticReader(prefix string, data interface{}) Reader {
+	return &static{
+		data:   data,
+		prefix: prefix,
+	}
+}
+
+// NewErrorReader creates an instance of the Reader returning the given error
+func NewErrorReader(err error) Reader {
+	return &static{
+		err: err,
+	}
+}
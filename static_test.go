@@ -0,0 +1,32 @@
+package conf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestStaticReader(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStaticReader("", map[string]interface{}{"foo": 1, "bar": 2}),
+		conf.NewStaticReader("bar", "baz"),
+	)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, 1, c.GetInt("foo"))
+	require.Equal(t, "baz", c.GetString("bar"))
+}
+
+func TestErrorReader(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithReaders(
+		conf.NewStaticReader("", map[string]interface{}{"foo": 1}),
+		conf.NewErrorReader(errFake),
+	)
+	require.ErrorIs(t, c.Load(context.Background()), errFake)
+}
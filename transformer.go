@@ -1,4 +1,138 @@
 package conf
 
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrEmptyValue is an error returned by the RequireNonEmpty transformer if a value is empty
+var ErrEmptyValue = errors.New("empty value")
+
 // Transform is a function to transform the data
 type Transform func(key string, value interface{}, c Conf) interface{}
+
+// TransformE is a function to transform the data or to reject it with an error, see WithTransformersE
+type TransformE func(key string, value interface{}, c Conf) (interface{}, error)
+
+// stopped is a value returned by a transformer to halt the transformers chain
+type stopped struct {
+	value interface{}
+}
+
+// StopTransform wraps a given value to halt the transformers chain
+// A transformer returns the wrapped value to finalize it, so the remaining transformers are not applied
+// and the Get function returns the given value.
+//
+// Example:
+//
+//	func(key string, value interface{}, _ conf.Conf) interface{} {
+//		if key == "raw" {
+//			return conf.StopTransform(value)
+//		}
+//		return value
+//	}
+func StopTransform(value interface{}) interface{} {
+	return stopped{value: value}
+}
+
+var (
+	intRegexp   = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	floatRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)\.[0-9]+$`)
+)
+
+// CoerceScalars returns a transformer to convert the string values looking like numbers or booleans
+// into the typed values, e.g. the values of the environment variables.
+// Only unambiguous values are converted:
+//   - `true` and `false` in any case to Bool
+//   - decimal integers without leading zeros and plus sign to Int
+//   - decimal numbers with a fraction part and without exponent to Float64
+//
+// All other values, including `1.2.3`, `007` or `1e5`, are returned as is.
+func CoerceScalars() Transform {
+	return func(_ string, value interface{}, _ Conf) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+
+		switch {
+		case s == "true" || s == "True" || s == "TRUE":
+			return true
+		case s == "false" || s == "False" || s == "FALSE":
+			return false
+		case intRegexp.MatchString(s):
+			if v, err := strconv.Atoi(s); err == nil {
+				return v
+			}
+		case floatRegexp.MatchString(s):
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				return v
+			}
+		}
+
+		return value
+	}
+}
+
+// Redact returns a transformer to replace the values by a given redaction function
+// The function gets every key and value and returns the value to be used instead,
+// so the logic to hide the sensitive values is fully controlled by the caller, e.g. keeping a part of a token.
+// It is intended for the instances used to log or to expose the configuration.
+//
+// Example:
+//
+//	conf.Redact(func(key string, value interface{}) interface{} {
+//		if strings.HasSuffix(key, "password") {
+//			return "***"
+//		}
+//		return value
+//	})
+func Redact(fn func(key string, value interface{}) interface{}) Transform {
+	return func(key string, value interface{}, _ Conf) interface{} {
+		return fn(key, value)
+	}
+}
+
+// RequireNonEmpty returns an error-returning transformer to reject the empty values of the matched keys
+// The keys are matched by the patterns using the `path.Match` syntax, e.g. `db.*` or `api.token`.
+// The value is empty if it is not set or it is a string containing the white spaces only.
+// The values of other types and of the unmatched keys are returned as is.
+// The getters without error return the zero values for the rejected keys,
+// use WithStrictCast to report them at access time.
+//
+// Example:
+//
+//	conf.WithTransformersE(conf.RequireNonEmpty("db.host", "api.*"))
+//	_, err := conf.GetE("db.host") // ErrEmptyValue if the host is not set
+func RequireNonEmpty(keyPatterns ...string) TransformE {
+	return func(key string, value interface{}, _ Conf) (interface{}, error) {
+		if !matchAny(keyPatterns, key) {
+			return value, nil
+		}
+
+		switch v := value.(type) {
+		case nil:
+			return nil, ErrEmptyValue
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return nil, ErrEmptyValue
+			}
+		}
+
+		return value, nil
+	}
+}
+
+// matchAny reports whether a given key matches any of given patterns
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,225 @@
+package conf_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestCoerceScalars(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithTransformers(conf.CoerceScalars())
+	data := map[string]interface{}{
+		"42":                   42,
+		"-42":                  -42,
+		"0":                    0,
+		"true":                 true,
+		"TRUE":                 true,
+		"False":                false,
+		"1.25":                 1.25,
+		"-0.5":                 -0.5,
+		"1.2.3":                "1.2.3",
+		"007":                  "007",
+		"+1":                   "+1",
+		"1e5":                  "1e5",
+		"NaN":                  "NaN",
+		"yes":                  "yes",
+		"t":                    "t",
+		"":                     "",
+		"99999999999999999999": "99999999999999999999",
+	}
+	for rawValue, expectedValue := range data {
+		c.Set("flag", rawValue)
+
+		require.Equal(t, expectedValue, c.Get("flag"), rawValue)
+	}
+
+	c.Set("flag", 42.5)
+	require.Equal(t, 42.5, c.Get("flag"))
+
+	c.Set("foo.bar", "42")
+	require.Equal(t, map[string]interface{}{"bar": 42}, c.AllSettings()["foo"])
+}
+
+func TestStopTransform(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	c := conf.New().WithTransformers(
+		func(key string, value interface{}, _ conf.Conf) interface{} {
+			calls = append(calls, "first "+key)
+			if key == "final" {
+				return conf.StopTransform("finalized")
+			}
+			return value
+		},
+		func(key string, value interface{}, _ conf.Conf) interface{} {
+			calls = append(calls, "second "+key)
+			return "transformed"
+		},
+	)
+	c.Set("final", "foo")
+	c.Set("other", "bar")
+
+	require.Equal(t, "finalized", c.Get("final"))
+	require.Equal(t, []string{"first final"}, calls)
+
+	require.Equal(t, "transformed", c.Get("other"))
+	require.Equal(t, []string{"first final", "first other", "second other"}, calls)
+}
+
+func TestWithRecoverTransformers(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	c := conf.New().WithTransformers(
+		func(_ string, value interface{}, _ conf.Conf) interface{} {
+			return value.(string) + "!"
+		},
+		func(_ string, value interface{}, _ conf.Conf) interface{} {
+			if value == "boom!" {
+				panic("something went wrong")
+			}
+			return value
+		},
+	)
+	c.Set("foo", "bar")
+	c.Set("bad", "boom")
+
+	require.Panics(t, func() { c.Get("bad") })
+
+	c.WithRecoverTransformers(slog.New(slog.NewTextHandler(&buf, nil)))
+	require.Equal(t, "bar!", c.Get("foo"))
+	require.Empty(t, buf.String())
+
+	require.Equal(t, "boom", c.Get("bad"))
+	require.Contains(t, buf.String(), `msg="transformer panicked" key=bad panic="something went wrong"`)
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithTransformers(conf.Redact(func(key string, value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok || !strings.HasSuffix(key, "token") || len(s) <= 8 {
+			return value
+		}
+		return s[:4] + strings.Repeat("*", len(s)-8) + s[len(s)-4:]
+	}))
+	c.Set("api.token", "abcd1234567890wxyz")
+	c.Set("api.short_token", "abcd")
+	c.Set("api.url", "https://example.com")
+	c.Set("api.retries", 3)
+
+	require.Equal(t, "abcd**********wxyz", c.Get("api.token"))
+	require.Equal(t, "abcd", c.Get("api.short_token"))
+	require.Equal(t, "https://example.com", c.Get("api.url"))
+	require.Equal(t, 3, c.Get("api.retries"))
+}
+
+func TestRequireNonEmpty(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithTransformersE(conf.RequireNonEmpty("db.host", "api.*"))
+	c.Set("db.host", "localhost")
+	c.Set("api.token", "  ")
+	c.Set("api.retries", 0)
+	c.Set("name", "")
+
+	value, err := c.GetE("db.host")
+	require.NoError(t, err)
+	require.Equal(t, "localhost", value)
+
+	value, err = c.GetE("api.token")
+	require.ErrorIs(t, err, conf.ErrEmptyValue)
+	require.ErrorIs(t, err, conf.ErrInvalidValue)
+	require.Nil(t, value)
+	require.Nil(t, c.Get("api.token"))
+	require.Equal(t, "default", c.GetStringD("api.token", "default"))
+
+	_, err = c.GetE("api.url")
+	require.ErrorIs(t, err, conf.ErrEmptyValue)
+
+	value, err = c.GetE("api.retries")
+	require.NoError(t, err)
+	require.Equal(t, 0, value)
+
+	value, err = c.GetE("name")
+	require.NoError(t, err)
+	require.Empty(t, value)
+
+	c.Set("api.token", "secret")
+	value, err = c.GetE("api.token")
+	require.NoError(t, err)
+	require.Equal(t, "secret", value)
+}
+
+func TestRequireNonEmpty_StrictCast(t *testing.T) {
+	t.Parallel()
+
+	c := conf.New().WithTransformersE(conf.RequireNonEmpty("db.host")).WithStrictCast(nil)
+	require.PanicsWithError(t, `invalid value "db.host": empty value`, func() {
+		c.GetString("db.host")
+	})
+	c.Set("db.host", "localhost")
+	require.Equal(t, "localhost", c.GetString("db.host"))
+
+	var buf bytes.Buffer
+	c = conf.New().
+		WithTransformersE(conf.RequireNonEmpty("db.host")).
+		WithStrictCast(slog.New(slog.NewTextHandler(&buf, nil)))
+	require.Equal(t, "default", c.GetStringD("db.host", "default"))
+	require.Contains(t, buf.String(), `msg="failed to cast configuration value" key=db.host error="invalid value`)
+}
+
+func TestConf_WithTransformersE(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	c := conf.New().
+		WithTransformers(func(_ string, value interface{}, _ conf.Conf) interface{} {
+			if s, ok := value.(string); ok {
+				return strings.TrimSpace(s)
+			}
+			return value
+		}).
+		WithTransformersE(
+			func(key string, value interface{}, _ conf.Conf) (interface{}, error) {
+				if key == "bad" {
+					return nil, errFake
+				}
+				return value, nil
+			},
+			func(_ string, value interface{}, _ conf.Conf) (interface{}, error) {
+				calls++
+				return value, nil
+			},
+		)
+	c.Set("good", " foo ")
+	c.Set("bad", "bar")
+
+	value, err := c.GetE("good")
+	require.NoError(t, err)
+	require.Equal(t, "foo", value)
+	require.Equal(t, 1, calls)
+
+	_, err = c.GetE("bad")
+	require.ErrorIs(t, err, errFake)
+	require.EqualError(t, err, `invalid value "bad": fake error`)
+	require.Equal(t, 1, calls)
+
+	var keys []string
+	c.Walk(func(key string, value interface{}) bool {
+		if value != nil {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	require.Equal(t, []string{"good"}, keys)
+}
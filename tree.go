@@ -0,0 +1,105 @@
+package conf
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// tree reconstructs a nested structure of the values stored under a given key
+// The transformers are applied to the leaf values.
+// Returns false if there are no keys under the given key.
+func (c *conf) tree(key string) (interface{}, bool) {
+	if key != "" {
+		key = c.normalizeKey(key)
+	}
+
+	var keys []string
+	for _, k := range c.Keys() {
+		switch {
+		case key == "":
+			keys = append(keys, k)
+		case k == key:
+			keys = append(keys, "")
+		case strings.HasPrefix(k, key+"."):
+			keys = append(keys, k[len(key)+1:])
+		}
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	if len(keys) == 0 {
+		return nil, false
+	}
+	if len(keys) == 1 && keys[0] == "" {
+		return c.Get(key), true
+	}
+
+	parents := map[string]struct{}{}
+	for _, k := range keys {
+		for i := range k {
+			if k[i] == '.' {
+				parents[k[:i]] = struct{}{}
+			}
+		}
+	}
+
+	root := map[string]interface{}{}
+	for _, k := range keys {
+		if _, ok := parents[k]; ok || k == "" {
+			continue
+		}
+
+		node := root
+		segments := strings.Split(k, ".")
+		for _, segment := range segments[:len(segments)-1] {
+			child, ok := node[segment].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[segment] = child
+			}
+			node = child
+		}
+		node[segments[len(segments)-1]] = c.Get(joinKey(key, k))
+	}
+
+	return c.restoreSlices(root, key), true
+}
+
+func joinKey(prefix, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	default:
+		return prefix + "." + key
+	}
+}
+
+// restoreSlices converts the maps with the index keys back to the slices,
+// if the original value stored for a given key is a slice or an array
+func (c *conf) restoreSlices(node map[string]interface{}, key string) interface{} {
+	for k, v := range node {
+		if child, ok := v.(map[string]interface{}); ok {
+			node[k] = c.restoreSlices(child, joinKey(key, k))
+		}
+	}
+
+	raw, _ := c.lookup(key)
+	if kind := reflect.ValueOf(raw).Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return node
+	}
+
+	res := make([]interface{}, len(node))
+	for k, v := range node {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(res) {
+			return node
+		}
+		res[i] = v
+	}
+
+	return res
+}
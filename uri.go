@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrUnknownScheme is an error returned if there is no reader registered for a scheme of URI
+var ErrUnknownScheme = errors.New("unknown scheme")
+
+// Schemes is a global extendable list of the functions creating the readers by a scheme of URI
+// The `file` and `env` schemes are registered by default:
+//   - `file:///etc/app/config.json` reads the file using the parser selected by the extension, see NewAutoFileParser
+//   - `env://APP_` reads the environment variables with the `APP_` prefix, see NewEnvReader
+//
+// Example:
+//
+//	conf.Schemes["https"] = func(u *url.URL) (conf.Reader, error) {
+//		return httpReader.New(u.String()), nil
+//	}
+var Schemes = map[string]func(u *url.URL) (Reader, error){
+	"file": func(u *url.URL) (Reader, error) {
+		return NewAutoFileParser(u.Host + u.Path)
+	},
+	"env": func(u *url.URL) (Reader, error) {
+		return NewEnvReader(u.Host), nil
+	},
+}
+
+// NewReaderFromURI creates an instance of the Reader selected by the scheme of a given URI
+// Can be used to configure the source by a single string, e.g. an environment variable.
+func NewReaderFromURI(uri string) (Reader, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, ok := Schemes[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScheme, u.Scheme)
+	}
+
+	return fn(u)
+}
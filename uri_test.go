@@ -0,0 +1,54 @@
+package conf_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sv-tools/conf"
+)
+
+func TestNewReaderFromURI_File(t *testing.T) {
+	t.Parallel()
+
+	path, err := filepath.Abs("testdata/data.json")
+	require.NoError(t, err)
+
+	for _, uri := range []string{"file://" + path, "file://testdata/data.json"} {
+		reader, err := conf.NewReaderFromURI(uri)
+		require.NoError(t, err, uri)
+
+		c := conf.New().WithReaders(reader)
+		require.NoError(t, c.Load(context.Background()), uri)
+		require.Equal(t, 1, c.GetInt("foo"), uri)
+		require.Equal(t, "qux", c.GetString("bar.baz"), uri)
+	}
+
+	_, err = conf.NewReaderFromURI("file://testdata/fake.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewReaderFromURI_Env(t *testing.T) {
+	t.Setenv("CONF_URI_DB_HOST", "localhost")
+
+	reader, err := conf.NewReaderFromURI("env://CONF_URI_")
+	require.NoError(t, err)
+
+	c := conf.New().WithReaders(reader)
+	require.NoError(t, c.Load(context.Background()))
+	require.Equal(t, "localhost", c.GetString("db.host"))
+}
+
+func TestNewReaderFromURI_ErrUnknownScheme(t *testing.T) {
+	t.Parallel()
+
+	reader, err := conf.NewReaderFromURI("s3://bucket/config.json")
+	require.ErrorIs(t, err, conf.ErrUnknownScheme)
+	require.Nil(t, reader)
+
+	_, err = conf.NewReaderFromURI("://")
+	require.Error(t, err)
+}